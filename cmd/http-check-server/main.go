@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,10 +12,17 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/config"
+	"github.com/MauveSoftware/http-check/internal/probe"
+	"github.com/MauveSoftware/http-check/internal/scheduler"
 	"github.com/MauveSoftware/http-check/internal/server"
+	"github.com/MauveSoftware/http-check/internal/tlsutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -22,11 +31,21 @@ const (
 )
 
 var (
-	showVersion = kingpin.Flag("version", "Show version info").Bool()
-	workerCount = kingpin.Flag("worker-count", "Number of workers processing http checks in parallel").Default("25").Uint32()
-	timeout     = kingpin.Flag("timeout", "Request timeout").Default("10s").Duration()
-	tlsTimeout  = kingpin.Flag("tls-timeout", "TLS connect timeout").Default("1s").Duration()
-	socketPath  = kingpin.Flag("socket-path", "Socket to create to listen for check requests").Default("/tmp/http-check.sock").String()
+	showVersion   = kingpin.Flag("version", "Show version info").Bool()
+	workerCount   = kingpin.Flag("worker-count", "Number of workers processing http checks in parallel").Default("25").Uint32()
+	timeout       = kingpin.Flag("timeout", "Request timeout").Default("10s").Duration()
+	tlsTimeout    = kingpin.Flag("tls-timeout", "TLS connect timeout").Default("1s").Duration()
+	queueTimeout  = kingpin.Flag("queue-timeout", "Maximum time a check waits for a free worker").Default("10s").Duration()
+	maxBodySize   = kingpin.Flag("max-body-size", "Maximum size of the response body buffered for assertions").Default("4MB").Bytes()
+	configFile    = kingpin.Flag("config-file", "Configuration file (YAML) containing e.g. named client certificates").ExistingFile()
+	authFile      = kingpin.Flag("auth-file", "YAML file mapping auth names to auth definitions (same format as the auth section of the config file)").ExistingFile()
+	authDir       = kingpin.Flag("auth-dir", "Secrets directory containing a sub directory (username, password, token, tls.crt, tls.key) per auth").ExistingDir()
+	socketPath    = kingpin.Flag("socket-path", "Socket to create to listen for check requests (empty to disable)").Default("/tmp/http-check.sock").String()
+	listenAddress = kingpin.Flag("listen-address", "TCP address to listen for check requests (requires --tls-cert and --tls-key)").String()
+	tlsCert       = kingpin.Flag("tls-cert", "Certificate file (PEM) used for the TCP listener").ExistingFile()
+	tlsKey        = kingpin.Flag("tls-key", "Private key file (PEM) used for the TCP listener").ExistingFile()
+	tlsClientCA   = kingpin.Flag("tls-client-ca", "CA file (PEM) to verify client certificates. If set clients on the TCP listener have to authenticate with a certificate").ExistingFile()
+	metricsAddr   = kingpin.Flag("metrics-address", "TCP address to expose Prometheus metrics of scheduled checks (/metrics) and the probe endpoint (/probe) on (empty to disable)").String()
 )
 
 func main() {
@@ -37,21 +56,69 @@ func main() {
 		os.Exit(0)
 	}
 
-	lis, err := openSocket()
+	if len(*socketPath) == 0 && len(*listenAddress) == 0 {
+		logrus.Fatal("Neither a socket path nor a listen address was specified")
+	}
+
+	cfg := &config.Config{}
+	if len(*configFile) > 0 {
+		var err error
+		cfg, err = config.Load(*configFile)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	}
+
+	err := loadAuth(cfg)
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	defer lis.Close()
 
-	srv := grpc.NewServer()
 	logrus.Infof("Starting %d workers", *workerCount)
-	s := server.New(*workerCount, *timeout, *tlsTimeout)
-	api.RegisterHttpCheckServiceServer(srv, s)
+	s := server.New(*workerCount, *timeout, *tlsTimeout, *queueTimeout,
+		server.WithMaxBodySize(int64(*maxBodySize)),
+		server.WithConfig(cfg))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if len(cfg.Checks) > 0 {
+		err := startScheduler(ctx, s, cfg)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	}
 
-	logrus.Infof("Listen for connections on socket %s", *socketPath)
-	go logrus.Error(srv.Serve(lis))
+	if len(*metricsAddr) > 0 {
+		logrus.Infof("Exposing metrics on %s/metrics and probes on %s/probe", *metricsAddr, *metricsAddr)
+		serveMetrics(s, cfg)
+	} else if len(cfg.Checks) > 0 || len(cfg.Modules) > 0 {
+		logrus.Warn("Checks or probe modules are configured but no metrics address was specified")
+	}
+
+	if len(*socketPath) > 0 {
+		lis, err := openSocket()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		defer lis.Close()
+
+		logrus.Infof("Listen for connections on socket %s", *socketPath)
+		serve(s, lis)
+	}
+
+	if len(*listenAddress) > 0 {
+		lis, creds, err := openTCPListener()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		defer lis.Close()
+
+		logrus.Infof("Listen for connections on %s", *listenAddress)
+		serve(s, lis, grpc.Creds(creds))
+	}
 
-	termChan := make(chan os.Signal)
+	termChan := make(chan os.Signal, 1)
 	signal.Notify(termChan, syscall.SIGINT, syscall.SIGTERM)
 
 	<-termChan
@@ -59,6 +126,35 @@ func main() {
 	cleanupSocket()
 }
 
+func serve(s api.HttpCheckServiceServer, lis net.Listener, opts ...grpc.ServerOption) {
+	srv := grpc.NewServer(opts...)
+	api.RegisterHttpCheckServiceServer(srv, s)
+
+	go func() {
+		logrus.Error(srv.Serve(lis))
+	}()
+}
+
+func startScheduler(ctx context.Context, s *server.HTTPCheckServer, cfg *config.Config) error {
+	sched, err := scheduler.New(s, cfg.Checks, prometheus.DefaultRegisterer)
+	if err != nil {
+		return err
+	}
+
+	sched.Start(ctx)
+	return nil
+}
+
+func serveMetrics(s *server.HTTPCheckServer, cfg *config.Config) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/probe", probe.NewHandler(s, cfg.Modules))
+
+	go func() {
+		logrus.Error(http.ListenAndServe(*metricsAddr, mux))
+	}()
+}
+
 func openSocket() (net.Listener, error) {
 	cleanupSocket()
 	lis, err := net.Listen("unix", *socketPath)
@@ -69,7 +165,29 @@ func openSocket() (net.Listener, error) {
 	return lis, nil
 }
 
+func openTCPListener() (net.Listener, credentials.TransportCredentials, error) {
+	if len(*tlsCert) == 0 || len(*tlsKey) == 0 {
+		return nil, nil, fmt.Errorf("TLS certificate and key are required to listen on %s", *listenAddress)
+	}
+
+	cfg, err := tlsutil.ServerConfig(*tlsCert, *tlsKey, *tlsClientCA)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lis, err := net.Listen("tcp", *listenAddress)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Failed to listen on %s", *listenAddress)
+	}
+
+	return lis, credentials.NewTLS(cfg), nil
+}
+
 func cleanupSocket() {
+	if len(*socketPath) == 0 {
+		return
+	}
+
 	_, err := os.Stat(*socketPath)
 	if os.IsNotExist(err) {
 		return
@@ -81,6 +199,44 @@ func cleanupSocket() {
 	}
 }
 
+// loadAuth adds the auth from the auth file, the secrets directory and the environment
+func loadAuth(cfg *config.Config) error {
+	if len(*authFile) > 0 {
+		auth, err := config.LoadAuthFile(*authFile)
+		if err != nil {
+			return err
+		}
+
+		err = cfg.AddAuth(auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(*authDir) > 0 {
+		auth, err := config.LoadAuthDir(*authDir)
+		if err != nil {
+			return err
+		}
+
+		err = cfg.AddAuth(auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := cfg.AddAuth(config.AuthFromEnv(os.Environ()))
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Auth) > 0 {
+		logrus.Infof("Loaded %d auth definitions", len(cfg.Auth))
+	}
+
+	return nil
+}
+
 func printVersion() {
 	fmt.Println("http-check-server")
 	fmt.Printf("Version: %s\n", version)
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net"
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/tlsutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// connect establishes a connection to the check server, either via unix socket or via TCP secured by TLS
+func connect() (*grpc.ClientConn, error) {
+	if len(*serverAddress) == 0 {
+		return grpc.Dial(
+			*socketPath,
+			grpc.WithInsecure(),
+			grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+				return net.DialTimeout("unix", addr, timeout)
+			}))
+	}
+
+	cfg, err := tlsutil.ClientConfig(*serverCA, *clientCert, *clientKey)
+	if err != nil {
+		return nil, err
+	}
+
+	host, _, err := net.SplitHostPort(*serverAddress)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ServerName = host
+
+	return grpc.Dial(*serverAddress, grpc.WithTransportCredentials(credentials.NewTLS(cfg)))
+}
+
+// serverDescription returns the address of the check server used in error messages
+func serverDescription() string {
+	if len(*serverAddress) > 0 {
+		return *serverAddress
+	}
+
+	return *socketPath
+}
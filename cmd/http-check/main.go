@@ -3,13 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
-	"net"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/MauveSoftware/http-check/internal/api"
-	"github.com/sirupsen/logrus"
-	"google.golang.org/grpc"
+	"github.com/MauveSoftware/http-check/internal/definition"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -18,79 +19,321 @@ const (
 )
 
 var (
+	httpCommand = kingpin.Command("http", "Perform a HTTP(S) check (default)").Default()
+	tlsCommand  = kingpin.Command("tls", "Perform a TLS handshake with host:port without sending a HTTP request (e.g. SMTPS, IMAPS, LDAPS)")
+	sni         = tlsCommand.Flag("sni", "Server name to send via SNI and to verify the certificate for (default: host)").String()
+	startTLS    = tlsCommand.Flag("starttls", "Negotiate TLS using STARTTLS of the plaintext protocol").Enum("smtp", "imap", "pop3", "ftp")
+)
+
+var (
+	configFile         = kingpin.Flag("config", "File (YAML or JSON) containing check definitions").ExistingFile()
+	checkName          = kingpin.Flag("name", "Name of the check definition in the config file to run").String()
 	verbose            = kingpin.Flag("verbose", "Verbose mode").Short('v').Bool()
 	showVersion        = kingpin.Flag("version", "Show version info").Bool()
 	protocol           = kingpin.Flag("protocol", "Protocol to use for the request").Default("https").String()
 	host               = kingpin.Flag("host", "Hostname to use for the request").Short('h').String()
 	path               = kingpin.Flag("path", "Path to use for the request").String()
+	method             = kingpin.Flag("method", "HTTP method to use for the request (default: GET, POST if data is specified)").Short('X').String()
+	headers            = kingpin.Flag("header", "Header to send with the request (format: 'Name: value')").Short('H').Strings()
+	data               = kingpin.Flag("data", "Data to send as request body").Short('d').String()
+	dataFile           = kingpin.Flag("data-file", "File containing the data to send as request body").ExistingFile()
+	cookieJar          = kingpin.Flag("cookie-jar", "Store cookies set by responses (e.g. on redirects) and send them with the following requests of the check").Bool()
+	cookies            = kingpin.Flag("cookie", "Cookie to send with the request (format: 'name=value', repeatable)").Strings()
+	noFollow           = kingpin.Flag("no-follow", "Do not follow redirects (the redirect response is validated)").Bool()
+	maxRedirects       = kingpin.Flag("max-redirects", "Maximum number of redirects to follow (0: do not follow redirects)").Default("10").Uint32()
 	username           = kingpin.Flag("username", "Username to use for authentication").Short('u').String()
-	password           = kingpin.Flag("password", "Password to use for authentication").Short('p').String()
+	password           = kingpin.Flag("password", "Password to use for authentication (visible in the process list, consider using --auth)").Short('p').String()
+	authScheme         = kingpin.Flag("auth-scheme", "Scheme used to authenticate with username and password (basic, digest or ntlm)").PlaceHolder("basic").Enum("basic", "digest", "ntlm")
+	auth               = kingpin.Flag("auth", "Name of an auth (OAuth2 client credentials, bearer token, API key, basic auth or client certificate) configured on the server").String()
 	expectedStatusCode = kingpin.Flag("expect-status", "List of expected status codes").Short('s').Uint32List()
+	expectedHeaders    = kingpin.Flag("expect-header", "Expected header (format: 'Name' (present), '!Name' (absent), 'Name==value', 'Name~=regex' or 'Name*=substring')").Strings()
 	expectedBody       = kingpin.Flag("expect-body-string", "Expected string in response body").Short('b').String()
 	expectedBodyRegex  = kingpin.Flag("expect-body-regex", "Expected regex matching string in response body").Short('r').String()
+	expectedFinalURL   = kingpin.Flag("expect-final-url", "Expected URL after following all redirects").String()
+	expectedLocation   = kingpin.Flag("expect-location", "Expected Location header (e.g. in combination with --no-follow)").String()
+	expectedCookies    = kingpin.Flag("expect-cookie", "Expected cookie set by the server (format: 'name[;Secure][;HttpOnly][;SameSite=Strict|Lax|None]', repeatable)").Strings()
+	expectedRedirects  = kingpin.Flag("expect-redirect", "Expected URL in the redirect chain, starting with the requested URL and ending with the final URL (repeatable)").Strings()
+	expectedJSON       = kingpin.Flag("expect-json", "Expected JSON value (format: '$.path' (exists), '$.path==value', '$.path>=number' or 'length($.path)>number')").Short('j').Strings()
+	expectedCSS        = kingpin.Flag("expect-css", "Expected CSS selector in HTML body (format: 'sel' (exists), 'sel==text', 'sel~=regex', 'attr(sel,name)==value' or 'count(sel)>=number')").Strings()
+	expectedXPath      = kingpin.Flag("expect-xpath", "Expected XPath in HTML body (same format as --expect-css)").Strings()
+	expectedXMLXPath   = kingpin.Flag("expect-xml-xpath", "Expected XPath in XML body (same format as --expect-css)").Strings()
 	certExpireDays     = kingpin.Flag("cert-min-expire-days", "Minimum number of days until certificate expiration").Uint32()
+	certExpireWarning  = kingpin.Flag("cert-expire-warning", "Warning threshold for days until certificate expiration (nagios range format, e.g. 30:)").String()
+	certExpireCritical = kingpin.Flag("cert-expire-critical", "Critical threshold for days until certificate expiration (nagios range format, e.g. 14:)").String()
+	certVerifiedChains = kingpin.Flag("cert-verified-chains", "Include the verified chains (e.g. root certificates) in the certificate expiration check").Bool()
+	tlsMinVersion      = kingpin.Flag("tls-min-version", "Minimum negotiated TLS version (1.0, 1.1, 1.2 or 1.3)").String()
+	tlsCiphers         = kingpin.Flag("tls-cipher", "Allowed cipher suite, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 (repeatable)").Strings()
+	expectHostnameSAN  = kingpin.Flag("expect-hostname-in-san", "Expect the requested hostname in the SANs of the certificate").Bool()
+	expectedSANs       = kingpin.Flag("expect-san", "Expected name in the SANs of the certificate (repeatable)").Strings()
+	intermediateWarn   = kingpin.Flag("intermediate-expire-warning", "Warning threshold for days until expiration of intermediate certificates (nagios range format, e.g. 30:)").String()
+	intermediateCrit   = kingpin.Flag("intermediate-expire-critical", "Critical threshold for days until expiration of intermediate certificates (nagios range format, e.g. 14:)").String()
+	expectedIssuerCN   = kingpin.Flag("expect-issuer-cn", "Expected common name of the certificate issuer").String()
+	expectedIssuerOrg  = kingpin.Flag("expect-issuer-org", "Expected organization of the certificate issuer").String()
+	expectOCSPStapled  = kingpin.Flag("expect-ocsp-stapled", "Expect a stapled OCSP response").Bool()
+	timeWarning        = kingpin.Flag("warning", "Warning threshold for the response time in seconds (nagios range format)").Short('w').String()
+	timeCritical       = kingpin.Flag("critical", "Critical threshold for the response time in seconds (nagios range format)").Short('c').String()
 	socketPath         = kingpin.Flag("socket-path", "Socket to use to communicate with the server performing the check").Default("/tmp/http-check.sock").String()
+	serverAddress      = kingpin.Flag("server-addr", "TCP address (host:port) of the check server. If set, TLS is used instead of the socket").String()
+	serverCA           = kingpin.Flag("ca", "CA file (PEM) to verify the certificate of the check server (default: system roots)").ExistingFile()
+	clientCert         = kingpin.Flag("cert", "Certificate file (PEM) to authenticate against the check server").ExistingFile()
+	clientKey          = kingpin.Flag("key", "Private key file (PEM) to authenticate against the check server").ExistingFile()
+	targetCertName     = kingpin.Flag("target-cert-name", "Name of a client certificate configured on the server to present to the checked host").String()
+	targetCertFile     = kingpin.Flag("target-cert-file", "Path (on the server) of a client certificate to present to the checked host").String()
+	targetKeyFile      = kingpin.Flag("target-key-file", "Path (on the server) of the private key of the client certificate").String()
+	targetTrustStore   = kingpin.Flag("target-trust-store", "Name of a trust store configured on the server to verify the certificate of the checked host").String()
+	targetCAFile       = kingpin.Flag("target-ca-file", "CA file (PEM) to verify the certificate of the checked host (sent to the server)").ExistingFile()
+	expectedPins       = kingpin.Flag("expect-pin", "Expected certificate pin, matching any certificate in the chain (format: 'sha256/<base64 SPKI hash>' or 'sha256:<hex fingerprint>', repeatable)").Strings()
 	insecure           = kingpin.Flag("insecure", "Allow invalid TLS certificaets (e.g. self signed)").Default("false").Bool()
+	serverTimeout      = kingpin.Flag("server-timeout", "Maximum time to wait for the check result from the server").Default("60s").Duration()
 )
 
+// clientFlags configure the client instead of the check, so they can be combined with --config
+var clientFlags = map[string]bool{
+	"config":         true,
+	"name":           true,
+	"verbose":        true,
+	"version":        true,
+	"help":           true,
+	"socket-path":    true,
+	"server-addr":    true,
+	"ca":             true,
+	"cert":           true,
+	"key":            true,
+	"server-timeout": true,
+}
+
 func main() {
-	kingpin.Parse()
+	cmd := kingpin.Parse()
 
 	if *showVersion {
 		printVersion()
 		os.Exit(0)
 	}
 
-	runCheck()
+	d, err := checkDefinition(cmd)
+	if err != nil {
+		exitUnknown(err.Error())
+	}
+
+	runCheck(d)
+}
+
+// checkDefinition returns the check definition loaded from the config file or defined by flags
+func checkDefinition(cmd string) (*definition.Definition, error) {
+	if len(*configFile) > 0 {
+		if len(*checkName) == 0 {
+			return nil, fmt.Errorf("--name is required when using --config")
+		}
+
+		if cmd == tlsCommand.FullCommand() {
+			return nil, fmt.Errorf("The tls command can not be used with --config (the type is defined by the check definition)")
+		}
+
+		flags, err := checkFlagsSet()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(flags) > 0 {
+			return nil, fmt.Errorf("%s can not be used with --config (the check is defined by the check definition)", strings.Join(flags, ", "))
+		}
+
+		f, err := definition.LoadFile(*configFile)
+		if err != nil {
+			return nil, err
+		}
+
+		d, err := f.Get(*checkName)
+		if err != nil {
+			return nil, err
+		}
+
+		d.Debug = d.Debug || *verbose
+		return d, nil
+	}
+
+	d := &definition.Definition{
+		Protocol:     *protocol,
+		Host:         *host,
+		Path:         *path,
+		Method:       *method,
+		Headers:      *headers,
+		Body:         *data,
+		BodyFile:     *dataFile,
+		Username:     *username,
+		Password:     *password,
+		Auth:         *auth,
+		AuthScheme:   *authScheme,
+		CookieJar:    *cookieJar,
+		Cookies:      *cookies,
+		NoFollow:     *noFollow,
+		MaxRedirects: maxRedirects,
+		SNI:          *sni,
+		StartTLS:     *startTLS,
+		Debug:        *verbose,
+		TLS: definition.TLS{
+			Insecure:       *insecure,
+			ClientCertName: *targetCertName,
+			ClientCertFile: *targetCertFile,
+			ClientKeyFile:  *targetKeyFile,
+			TrustStore:     *targetTrustStore,
+			CAFile:         *targetCAFile,
+			VerifiedChains: *certVerifiedChains,
+		},
+		Expect: definition.Expectations{
+			Status:            *expectedStatusCode,
+			Headers:           *expectedHeaders,
+			Body:              *expectedBody,
+			BodyRegex:         *expectedBodyRegex,
+			FinalURL:          *expectedFinalURL,
+			Location:          *expectedLocation,
+			Redirects:         *expectedRedirects,
+			Cookies:           *expectedCookies,
+			JSON:              *expectedJSON,
+			CSS:               *expectedCSS,
+			XPath:             *expectedXPath,
+			XMLXPath:          *expectedXMLXPath,
+			Pins:              *expectedPins,
+			CertMinExpireDays: *certExpireDays,
+			TLSMinVersion:     *tlsMinVersion,
+			TLSCiphers:        *tlsCiphers,
+			HostnameInSAN:     *expectHostnameSAN,
+			SANs:              *expectedSANs,
+			IssuerCN:          *expectedIssuerCN,
+			IssuerOrg:         *expectedIssuerOrg,
+			OCSPStapled:       *expectOCSPStapled,
+		},
+		Thresholds: definition.Thresholds{
+			ResponseTime:       definition.Threshold{Warning: *timeWarning, Critical: *timeCritical},
+			CertExpire:         definition.Threshold{Warning: *certExpireWarning, Critical: *certExpireCritical},
+			IntermediateExpire: definition.Threshold{Warning: *intermediateWarn, Critical: *intermediateCrit},
+		},
+	}
+
+	if cmd == tlsCommand.FullCommand() {
+		d.Type = "tls"
+	}
+
+	return d, nil
+}
+
+// checkFlagsSet returns the flags defining the check which are set on the command line
+func checkFlagsSet() ([]string, error) {
+	ctx, err := kingpin.CommandLine.ParseContext(os.Args[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, e := range ctx.Elements {
+		if f, ok := e.Clause.(*kingpin.FlagClause); ok && !clientFlags[f.Model().Name] {
+			res = append(res, "--"+f.Model().Name)
+		}
+	}
+
+	return res, nil
 }
 
-func runCheck() {
-	conn, err := grpc.Dial(
-		*socketPath,
-		grpc.WithInsecure(),
-		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
-			return net.DialTimeout("unix", addr, timeout)
-		}))
+func runCheck(d *definition.Definition) {
+	req, err := d.Request()
 	if err != nil {
-		logrus.Fatal(err)
+		exitUnknown(err.Error())
+	}
+
+	conn, err := connect()
+	if err != nil {
+		exitUnknown(fmt.Sprintf("Could not connect to check server: %v", err))
 	}
 	defer conn.Close()
 
 	c := api.NewHttpCheckServiceClient(conn)
 
-	req := &api.Request{
-		Protocol:           *protocol,
-		Host:               *host,
-		Path:               *path,
-		Username:           *username,
-		Password:           *password,
-		ExpectedStatusCode: *expectedStatusCode,
-		ExpectedBody:       *expectedBody,
-		ExpectedBodyRegex:  *expectedBodyRegex,
-		CertExpireDays:     *certExpireDays,
-		Debug:              *verbose,
-		Insecure:           *insecure,
-	}
-	resp, err := c.Check(context.Background(), req)
+	ctx, cancel := context.WithTimeout(context.Background(), *serverTimeout)
+	defer cancel()
+
+	resp, err := c.Check(ctx, req)
 	if err != nil {
-		logrus.Fatal(err)
+		exitUnknown(describeServerError(err))
 	}
 
-	exitCode := 0
-	status := "OK"
-
-	if !resp.Success {
-		status = "CRITICAL"
-		exitCode = 2
+	output := fmt.Sprintf("%s - %s", resp.Status, resp.Message)
+	if perf := perfData(resp, req); len(perf) > 0 {
+		output += " | " + perf
 	}
 
-	fmt.Printf("%s - %s\n", status, resp.Message)
+	fmt.Println(output)
+
+	if len(resp.Steps) > 0 {
+		for _, st := range resp.Steps {
+			fmt.Println(formatStep(st))
+
+			for _, a := range st.Assertions {
+				fmt.Println("    " + formatAssertion(a))
+			}
+		}
+	} else {
+		for _, a := range resp.Assertions {
+			fmt.Println(formatAssertion(a))
+		}
+	}
 
 	if len(resp.DebugMessage) > 0 {
 		fmt.Println(resp.DebugMessage)
 	}
 
-	os.Exit(exitCode)
+	os.Exit(exitCode(resp.Status))
+}
+
+func formatAssertion(a *api.AssertionResult) string {
+	if len(a.Message) == 0 {
+		return fmt.Sprintf("[%s] %s", a.Status, a.Name)
+	}
+
+	return fmt.Sprintf("[%s] %s: %s", a.Status, a.Name, a.Message)
+}
+
+func formatStep(s *api.StepResult) string {
+	res := fmt.Sprintf("[%s] Step '%s'", s.Status, s.Name)
+	if s.Performance != nil && s.Performance.TotalSeconds > 0 {
+		res += fmt.Sprintf(" (%v)", time.Duration(s.Performance.TotalSeconds*float64(time.Second)).Round(time.Millisecond))
+	}
+
+	return res + ": " + s.Message
+}
+
+func describeServerError(err error) string {
+	st := status.Convert(err)
+
+	switch st.Code() {
+	case codes.Unavailable:
+		return fmt.Sprintf("Check server unreachable (%s): %s", serverDescription(), st.Message())
+	case codes.InvalidArgument:
+		return fmt.Sprintf("Invalid request: %s", st.Message())
+	case codes.Internal:
+		return fmt.Sprintf("Internal error in check server: %s", st.Message())
+	case codes.DeadlineExceeded:
+		return fmt.Sprintf("Timeout waiting for check result: %s", st.Message())
+	default:
+		return st.Message()
+	}
+}
+
+func exitUnknown(msg string) {
+	fmt.Printf("%s - %s\n", api.Status_UNKNOWN, msg)
+	os.Exit(exitCode(api.Status_UNKNOWN))
+}
+
+func exitCode(s api.Status) int {
+	switch s {
+	case api.Status_OK:
+		return 0
+	case api.Status_WARNING:
+		return 1
+	case api.Status_CRITICAL:
+		return 2
+	default:
+		return 3
+	}
 }
 
 func printVersion() {
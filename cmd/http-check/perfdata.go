@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+)
+
+// perfValue represents a single nagios performance data value
+type perfValue struct {
+	label    string
+	value    float64
+	uom      string
+	warning  string
+	critical string
+	min      string
+}
+
+func (v perfValue) String() string {
+	label := v.label
+	if strings.ContainsAny(label, " =") {
+		label = "'" + label + "'"
+	}
+
+	return fmt.Sprintf("%s=%s%s;%s;%s;%s;",
+		label, strconv.FormatFloat(v.value, 'f', -1, 64), v.uom, v.warning, v.critical, v.min)
+}
+
+func perfData(resp *api.Response, req *api.Request) string {
+	p := resp.Performance
+	if p == nil {
+		return ""
+	}
+
+	values := []perfValue{
+		{label: "time", value: roundSeconds(p.TotalSeconds), uom: "s", warning: req.ResponseTimeWarning, critical: req.ResponseTimeCritical, min: "0"},
+		{label: "dns", value: roundSeconds(p.DnsLookupSeconds), uom: "s", min: "0"},
+		{label: "connect", value: roundSeconds(p.ConnectSeconds), uom: "s", min: "0"},
+		{label: "tls", value: roundSeconds(p.TlsHandshakeSeconds), uom: "s", min: "0"},
+	}
+
+	if req.CheckType != api.CheckType_TLS {
+		values = append(values,
+			perfValue{label: "ttfb", value: roundSeconds(p.FirstByteSeconds), uom: "s", min: "0"},
+			perfValue{label: "size", value: float64(p.BodySizeBytes), uom: "B", min: "0"})
+	}
+
+	if p.HasCertificate {
+		v := perfValue{
+			label:    "cert_expire_days",
+			value:    float64(int64(p.CertExpireDays)),
+			warning:  req.CertExpireWarning,
+			critical: req.CertExpireCritical,
+		}
+		if len(v.critical) == 0 && req.CertExpireDays > 0 {
+			v.critical = fmt.Sprintf("%d:", req.CertExpireDays)
+		}
+
+		values = append(values, v)
+	}
+
+	for _, st := range resp.Steps {
+		if st.Performance == nil {
+			continue
+		}
+
+		values = append(values, perfValue{
+			label: "step_" + strings.NewReplacer("'", "_", "=", "_").Replace(st.Name) + "_time",
+			value: roundSeconds(st.Performance.TotalSeconds),
+			uom:   "s",
+			min:   "0",
+		})
+	}
+
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = v.String()
+	}
+
+	return strings.Join(s, " ")
+}
+
+func roundSeconds(s float64) float64 {
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(s, 'f', 6, 64), 64)
+	return v
+}
@@ -24,21 +24,300 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type CheckType int32
+
+const (
+	CheckType_HTTP     CheckType = 0
+	CheckType_TLS      CheckType = 1
+	CheckType_SCENARIO CheckType = 2
+)
+
+var CheckType_name = map[int32]string{
+	0: "HTTP",
+	1: "TLS",
+	2: "SCENARIO",
+}
+
+var CheckType_value = map[string]int32{
+	"HTTP":     0,
+	"TLS":      1,
+	"SCENARIO": 2,
+}
+
+func (x CheckType) String() string {
+	return proto.EnumName(CheckType_name, int32(x))
+}
+
+func (CheckType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{0}
+}
+
+type RedirectMode int32
+
+const (
+	RedirectMode_FOLLOW    RedirectMode = 0
+	RedirectMode_NO_FOLLOW RedirectMode = 1
+)
+
+var RedirectMode_name = map[int32]string{
+	0: "FOLLOW",
+	1: "NO_FOLLOW",
+}
+
+var RedirectMode_value = map[string]int32{
+	"FOLLOW":    0,
+	"NO_FOLLOW": 1,
+}
+
+func (x RedirectMode) String() string {
+	return proto.EnumName(RedirectMode_name, int32(x))
+}
+
+func (RedirectMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{1}
+}
+
+type Status int32
+
+const (
+	Status_OK       Status = 0
+	Status_WARNING  Status = 1
+	Status_CRITICAL Status = 2
+	Status_UNKNOWN  Status = 3
+)
+
+var Status_name = map[int32]string{
+	0: "OK",
+	1: "WARNING",
+	2: "CRITICAL",
+	3: "UNKNOWN",
+}
+
+var Status_value = map[string]int32{
+	"OK":       0,
+	"WARNING":  1,
+	"CRITICAL": 2,
+	"UNKNOWN":  3,
+}
+
+func (x Status) String() string {
+	return proto.EnumName(Status_name, int32(x))
+}
+
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{2}
+}
+
+type Extraction_Source int32
+
+const (
+	Extraction_HEADER    Extraction_Source = 0
+	Extraction_REGEX     Extraction_Source = 1
+	Extraction_JSON_PATH Extraction_Source = 2
+)
+
+var Extraction_Source_name = map[int32]string{
+	0: "HEADER",
+	1: "REGEX",
+	2: "JSON_PATH",
+}
+
+var Extraction_Source_value = map[string]int32{
+	"HEADER":    0,
+	"REGEX":     1,
+	"JSON_PATH": 2,
+}
+
+func (x Extraction_Source) String() string {
+	return proto.EnumName(Extraction_Source_name, int32(x))
+}
+
+func (Extraction_Source) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{3, 0}
+}
+
+type HeaderAssertion_Mode int32
+
+const (
+	HeaderAssertion_EQUALS   HeaderAssertion_Mode = 0
+	HeaderAssertion_MATCHES  HeaderAssertion_Mode = 1
+	HeaderAssertion_CONTAINS HeaderAssertion_Mode = 2
+	HeaderAssertion_PRESENT  HeaderAssertion_Mode = 3
+	HeaderAssertion_ABSENT   HeaderAssertion_Mode = 4
+)
+
+var HeaderAssertion_Mode_name = map[int32]string{
+	0: "EQUALS",
+	1: "MATCHES",
+	2: "CONTAINS",
+	3: "PRESENT",
+	4: "ABSENT",
+}
+
+var HeaderAssertion_Mode_value = map[string]int32{
+	"EQUALS":   0,
+	"MATCHES":  1,
+	"CONTAINS": 2,
+	"PRESENT":  3,
+	"ABSENT":   4,
+}
+
+func (x HeaderAssertion_Mode) String() string {
+	return proto.EnumName(HeaderAssertion_Mode_name, int32(x))
+}
+
+func (HeaderAssertion_Mode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{4, 0}
+}
+
+type JSONPathAssertion_Type int32
+
+const (
+	JSONPathAssertion_EQUALS  JSONPathAssertion_Type = 0
+	JSONPathAssertion_EXISTS  JSONPathAssertion_Type = 1
+	JSONPathAssertion_COMPARE JSONPathAssertion_Type = 2
+	JSONPathAssertion_LENGTH  JSONPathAssertion_Type = 3
+)
+
+var JSONPathAssertion_Type_name = map[int32]string{
+	0: "EQUALS",
+	1: "EXISTS",
+	2: "COMPARE",
+	3: "LENGTH",
+}
+
+var JSONPathAssertion_Type_value = map[string]int32{
+	"EQUALS":  0,
+	"EXISTS":  1,
+	"COMPARE": 2,
+	"LENGTH":  3,
+}
+
+func (x JSONPathAssertion_Type) String() string {
+	return proto.EnumName(JSONPathAssertion_Type_name, int32(x))
+}
+
+func (JSONPathAssertion_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{5, 0}
+}
+
+type SelectorAssertion_SelectorType int32
+
+const (
+	SelectorAssertion_CSS        SelectorAssertion_SelectorType = 0
+	SelectorAssertion_XPATH_HTML SelectorAssertion_SelectorType = 1
+	SelectorAssertion_XPATH_XML  SelectorAssertion_SelectorType = 2
+)
+
+var SelectorAssertion_SelectorType_name = map[int32]string{
+	0: "CSS",
+	1: "XPATH_HTML",
+	2: "XPATH_XML",
+}
+
+var SelectorAssertion_SelectorType_value = map[string]int32{
+	"CSS":        0,
+	"XPATH_HTML": 1,
+	"XPATH_XML":  2,
+}
+
+func (x SelectorAssertion_SelectorType) String() string {
+	return proto.EnumName(SelectorAssertion_SelectorType_name, int32(x))
+}
+
+func (SelectorAssertion_SelectorType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{6, 0}
+}
+
+type SelectorAssertion_Type int32
+
+const (
+	SelectorAssertion_EXISTS           SelectorAssertion_Type = 0
+	SelectorAssertion_TEXT_EQUALS      SelectorAssertion_Type = 1
+	SelectorAssertion_TEXT_MATCHES     SelectorAssertion_Type = 2
+	SelectorAssertion_ATTRIBUTE_EQUALS SelectorAssertion_Type = 3
+	SelectorAssertion_COUNT            SelectorAssertion_Type = 4
+)
+
+var SelectorAssertion_Type_name = map[int32]string{
+	0: "EXISTS",
+	1: "TEXT_EQUALS",
+	2: "TEXT_MATCHES",
+	3: "ATTRIBUTE_EQUALS",
+	4: "COUNT",
+}
+
+var SelectorAssertion_Type_value = map[string]int32{
+	"EXISTS":           0,
+	"TEXT_EQUALS":      1,
+	"TEXT_MATCHES":     2,
+	"ATTRIBUTE_EQUALS": 3,
+	"COUNT":            4,
+}
+
+func (x SelectorAssertion_Type) String() string {
+	return proto.EnumName(SelectorAssertion_Type_name, int32(x))
+}
+
+func (SelectorAssertion_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{6, 1}
+}
+
 type Request struct {
-	Protocol             string   `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
-	Host                 string   `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
-	Path                 string   `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
-	Username             string   `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
-	Password             string   `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
-	ExpectedStatusCode   []uint32 `protobuf:"varint,6,rep,packed,name=expected_status_code,json=expectedStatusCode,proto3" json:"expected_status_code,omitempty"`
-	ExpectedBody         string   `protobuf:"bytes,7,opt,name=expected_body,json=expectedBody,proto3" json:"expected_body,omitempty"`
-	ExpectedBodyRegex    string   `protobuf:"bytes,8,opt,name=expected_body_regex,json=expectedBodyRegex,proto3" json:"expected_body_regex,omitempty"`
-	CertExpireDays       uint32   `protobuf:"varint,9,opt,name=cert_expire_days,json=certExpireDays,proto3" json:"cert_expire_days,omitempty"`
-	Debug                bool     `protobuf:"varint,10,opt,name=debug,proto3" json:"debug,omitempty"`
-	Insecure             bool     `protobuf:"varint,11,opt,name=insecure,proto3" json:"insecure,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Protocol                   string               `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Host                       string               `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Path                       string               `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Username                   string               `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Password                   string               `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	ExpectedStatusCode         []uint32             `protobuf:"varint,6,rep,packed,name=expected_status_code,json=expectedStatusCode,proto3" json:"expected_status_code,omitempty"`
+	ExpectedBody               string               `protobuf:"bytes,7,opt,name=expected_body,json=expectedBody,proto3" json:"expected_body,omitempty"`
+	ExpectedBodyRegex          string               `protobuf:"bytes,8,opt,name=expected_body_regex,json=expectedBodyRegex,proto3" json:"expected_body_regex,omitempty"`
+	CertExpireDays             uint32               `protobuf:"varint,9,opt,name=cert_expire_days,json=certExpireDays,proto3" json:"cert_expire_days,omitempty"`
+	Debug                      bool                 `protobuf:"varint,10,opt,name=debug,proto3" json:"debug,omitempty"`
+	Insecure                   bool                 `protobuf:"varint,11,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	ResponseTimeWarning        string               `protobuf:"bytes,12,opt,name=response_time_warning,json=responseTimeWarning,proto3" json:"response_time_warning,omitempty"`
+	ResponseTimeCritical       string               `protobuf:"bytes,13,opt,name=response_time_critical,json=responseTimeCritical,proto3" json:"response_time_critical,omitempty"`
+	CertExpireWarning          string               `protobuf:"bytes,14,opt,name=cert_expire_warning,json=certExpireWarning,proto3" json:"cert_expire_warning,omitempty"`
+	CertExpireCritical         string               `protobuf:"bytes,15,opt,name=cert_expire_critical,json=certExpireCritical,proto3" json:"cert_expire_critical,omitempty"`
+	Method                     string               `protobuf:"bytes,16,opt,name=method,proto3" json:"method,omitempty"`
+	Headers                    []*Header            `protobuf:"bytes,17,rep,name=headers,proto3" json:"headers,omitempty"`
+	Body                       []byte               `protobuf:"bytes,18,opt,name=body,proto3" json:"body,omitempty"`
+	ExpectedJson               []*JSONPathAssertion `protobuf:"bytes,19,rep,name=expected_json,json=expectedJson,proto3" json:"expected_json,omitempty"`
+	ExpectedSelectors          []*SelectorAssertion `protobuf:"bytes,20,rep,name=expected_selectors,json=expectedSelectors,proto3" json:"expected_selectors,omitempty"`
+	ExpectedHeaders            []*HeaderAssertion   `protobuf:"bytes,21,rep,name=expected_headers,json=expectedHeaders,proto3" json:"expected_headers,omitempty"`
+	RedirectMode               RedirectMode         `protobuf:"varint,22,opt,name=redirect_mode,json=redirectMode,proto3,enum=api.RedirectMode" json:"redirect_mode,omitempty"`
+	MaxRedirects               uint32               `protobuf:"varint,23,opt,name=max_redirects,json=maxRedirects,proto3" json:"max_redirects,omitempty"`
+	ExpectedFinalUrl           string               `protobuf:"bytes,24,opt,name=expected_final_url,json=expectedFinalUrl,proto3" json:"expected_final_url,omitempty"`
+	ExpectedLocation           string               `protobuf:"bytes,25,opt,name=expected_location,json=expectedLocation,proto3" json:"expected_location,omitempty"`
+	ExpectedRedirectChain      []string             `protobuf:"bytes,26,rep,name=expected_redirect_chain,json=expectedRedirectChain,proto3" json:"expected_redirect_chain,omitempty"`
+	ClientCertName             string               `protobuf:"bytes,27,opt,name=client_cert_name,json=clientCertName,proto3" json:"client_cert_name,omitempty"`
+	ClientCertFile             string               `protobuf:"bytes,28,opt,name=client_cert_file,json=clientCertFile,proto3" json:"client_cert_file,omitempty"`
+	ClientKeyFile              string               `protobuf:"bytes,29,opt,name=client_key_file,json=clientKeyFile,proto3" json:"client_key_file,omitempty"`
+	TrustStoreName             string               `protobuf:"bytes,30,opt,name=trust_store_name,json=trustStoreName,proto3" json:"trust_store_name,omitempty"`
+	CaPem                      []byte               `protobuf:"bytes,31,opt,name=ca_pem,json=caPem,proto3" json:"ca_pem,omitempty"`
+	ExpectedPins               []string             `protobuf:"bytes,32,rep,name=expected_pins,json=expectedPins,proto3" json:"expected_pins,omitempty"`
+	MinTlsVersion              string               `protobuf:"bytes,33,opt,name=min_tls_version,json=minTlsVersion,proto3" json:"min_tls_version,omitempty"`
+	AllowedCipherSuites        []string             `protobuf:"bytes,34,rep,name=allowed_cipher_suites,json=allowedCipherSuites,proto3" json:"allowed_cipher_suites,omitempty"`
+	ExpectHostnameInSan        bool                 `protobuf:"varint,35,opt,name=expect_hostname_in_san,json=expectHostnameInSan,proto3" json:"expect_hostname_in_san,omitempty"`
+	ExpectedSans               []string             `protobuf:"bytes,36,rep,name=expected_sans,json=expectedSans,proto3" json:"expected_sans,omitempty"`
+	IntermediateExpireWarning  string               `protobuf:"bytes,37,opt,name=intermediate_expire_warning,json=intermediateExpireWarning,proto3" json:"intermediate_expire_warning,omitempty"`
+	IntermediateExpireCritical string               `protobuf:"bytes,38,opt,name=intermediate_expire_critical,json=intermediateExpireCritical,proto3" json:"intermediate_expire_critical,omitempty"`
+	ExpectedIssuerCn           string               `protobuf:"bytes,39,opt,name=expected_issuer_cn,json=expectedIssuerCn,proto3" json:"expected_issuer_cn,omitempty"`
+	ExpectedIssuerOrg          string               `protobuf:"bytes,40,opt,name=expected_issuer_org,json=expectedIssuerOrg,proto3" json:"expected_issuer_org,omitempty"`
+	ExpectOcspStapled          bool                 `protobuf:"varint,41,opt,name=expect_ocsp_stapled,json=expectOcspStapled,proto3" json:"expect_ocsp_stapled,omitempty"`
+	IncludeVerifiedChains      bool                 `protobuf:"varint,42,opt,name=include_verified_chains,json=includeVerifiedChains,proto3" json:"include_verified_chains,omitempty"`
+	CheckType                  CheckType            `protobuf:"varint,43,opt,name=check_type,json=checkType,proto3,enum=api.CheckType" json:"check_type,omitempty"`
+	ServerName                 string               `protobuf:"bytes,44,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	Starttls                   string               `protobuf:"bytes,45,opt,name=starttls,proto3" json:"starttls,omitempty"`
+	Steps                      []*Step              `protobuf:"bytes,46,rep,name=steps,proto3" json:"steps,omitempty"`
+	CookieJar                  bool                 `protobuf:"varint,47,opt,name=cookie_jar,json=cookieJar,proto3" json:"cookie_jar,omitempty"`
+	Cookies                    []*Cookie            `protobuf:"bytes,48,rep,name=cookies,proto3" json:"cookies,omitempty"`
+	ExpectedCookies            []string             `protobuf:"bytes,49,rep,name=expected_cookies,json=expectedCookies,proto3" json:"expected_cookies,omitempty"`
+	AuthName                   string               `protobuf:"bytes,50,opt,name=auth_name,json=authName,proto3" json:"auth_name,omitempty"`
+	AuthScheme                 string               `protobuf:"bytes,51,opt,name=auth_scheme,json=authScheme,proto3" json:"auth_scheme,omitempty"`
+	XXX_NoUnkeyedLiteral       struct{}             `json:"-"`
+	XXX_unrecognized           []byte               `json:"-"`
+	XXX_sizecache              int32                `json:"-"`
 }
 
 func (m *Request) Reset()         { *m = Request{} }
@@ -143,92 +422,1257 @@ func (m *Request) GetInsecure() bool {
 	return false
 }
 
-type Response struct {
-	Success              bool     `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	DebugMessage         string   `protobuf:"bytes,3,opt,name=debug_message,json=debugMessage,proto3" json:"debug_message,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *Request) GetResponseTimeWarning() string {
+	if m != nil {
+		return m.ResponseTimeWarning
+	}
+	return ""
 }
 
-func (m *Response) Reset()         { *m = Response{} }
-func (m *Response) String() string { return proto.CompactTextString(m) }
-func (*Response) ProtoMessage()    {}
-func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a0b84a42fa06f626, []int{1}
+func (m *Request) GetResponseTimeCritical() string {
+	if m != nil {
+		return m.ResponseTimeCritical
+	}
+	return ""
 }
 
-func (m *Response) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Response.Unmarshal(m, b)
+func (m *Request) GetCertExpireWarning() string {
+	if m != nil {
+		return m.CertExpireWarning
+	}
+	return ""
 }
-func (m *Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Response.Marshal(b, m, deterministic)
+
+func (m *Request) GetCertExpireCritical() string {
+	if m != nil {
+		return m.CertExpireCritical
+	}
+	return ""
 }
-func (m *Response) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Response.Merge(m, src)
+
+func (m *Request) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
 }
-func (m *Response) XXX_Size() int {
-	return xxx_messageInfo_Response.Size(m)
+
+func (m *Request) GetHeaders() []*Header {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
 }
-func (m *Response) XXX_DiscardUnknown() {
-	xxx_messageInfo_Response.DiscardUnknown(m)
+
+func (m *Request) GetBody() []byte {
+	if m != nil {
+		return m.Body
+	}
+	return nil
 }
 
-var xxx_messageInfo_Response proto.InternalMessageInfo
+func (m *Request) GetExpectedJson() []*JSONPathAssertion {
+	if m != nil {
+		return m.ExpectedJson
+	}
+	return nil
+}
 
-func (m *Response) GetSuccess() bool {
+func (m *Request) GetExpectedSelectors() []*SelectorAssertion {
 	if m != nil {
-		return m.Success
+		return m.ExpectedSelectors
+	}
+	return nil
+}
+
+func (m *Request) GetExpectedHeaders() []*HeaderAssertion {
+	if m != nil {
+		return m.ExpectedHeaders
+	}
+	return nil
+}
+
+func (m *Request) GetRedirectMode() RedirectMode {
+	if m != nil {
+		return m.RedirectMode
+	}
+	return RedirectMode_FOLLOW
+}
+
+func (m *Request) GetMaxRedirects() uint32 {
+	if m != nil {
+		return m.MaxRedirects
+	}
+	return 0
+}
+
+func (m *Request) GetExpectedFinalUrl() string {
+	if m != nil {
+		return m.ExpectedFinalUrl
+	}
+	return ""
+}
+
+func (m *Request) GetExpectedLocation() string {
+	if m != nil {
+		return m.ExpectedLocation
+	}
+	return ""
+}
+
+func (m *Request) GetExpectedRedirectChain() []string {
+	if m != nil {
+		return m.ExpectedRedirectChain
+	}
+	return nil
+}
+
+func (m *Request) GetClientCertName() string {
+	if m != nil {
+		return m.ClientCertName
+	}
+	return ""
+}
+
+func (m *Request) GetClientCertFile() string {
+	if m != nil {
+		return m.ClientCertFile
+	}
+	return ""
+}
+
+func (m *Request) GetClientKeyFile() string {
+	if m != nil {
+		return m.ClientKeyFile
+	}
+	return ""
+}
+
+func (m *Request) GetTrustStoreName() string {
+	if m != nil {
+		return m.TrustStoreName
+	}
+	return ""
+}
+
+func (m *Request) GetCaPem() []byte {
+	if m != nil {
+		return m.CaPem
+	}
+	return nil
+}
+
+func (m *Request) GetExpectedPins() []string {
+	if m != nil {
+		return m.ExpectedPins
+	}
+	return nil
+}
+
+func (m *Request) GetMinTlsVersion() string {
+	if m != nil {
+		return m.MinTlsVersion
+	}
+	return ""
+}
+
+func (m *Request) GetAllowedCipherSuites() []string {
+	if m != nil {
+		return m.AllowedCipherSuites
+	}
+	return nil
+}
+
+func (m *Request) GetExpectHostnameInSan() bool {
+	if m != nil {
+		return m.ExpectHostnameInSan
 	}
 	return false
 }
 
-func (m *Response) GetMessage() string {
+func (m *Request) GetExpectedSans() []string {
 	if m != nil {
-		return m.Message
+		return m.ExpectedSans
+	}
+	return nil
+}
+
+func (m *Request) GetIntermediateExpireWarning() string {
+	if m != nil {
+		return m.IntermediateExpireWarning
 	}
 	return ""
 }
 
-func (m *Response) GetDebugMessage() string {
+func (m *Request) GetIntermediateExpireCritical() string {
 	if m != nil {
-		return m.DebugMessage
+		return m.IntermediateExpireCritical
+	}
+	return ""
+}
+
+func (m *Request) GetExpectedIssuerCn() string {
+	if m != nil {
+		return m.ExpectedIssuerCn
+	}
+	return ""
+}
+
+func (m *Request) GetExpectedIssuerOrg() string {
+	if m != nil {
+		return m.ExpectedIssuerOrg
+	}
+	return ""
+}
+
+func (m *Request) GetExpectOcspStapled() bool {
+	if m != nil {
+		return m.ExpectOcspStapled
+	}
+	return false
+}
+
+func (m *Request) GetIncludeVerifiedChains() bool {
+	if m != nil {
+		return m.IncludeVerifiedChains
+	}
+	return false
+}
+
+func (m *Request) GetCheckType() CheckType {
+	if m != nil {
+		return m.CheckType
+	}
+	return CheckType_HTTP
+}
+
+func (m *Request) GetServerName() string {
+	if m != nil {
+		return m.ServerName
 	}
 	return ""
 }
 
+func (m *Request) GetStarttls() string {
+	if m != nil {
+		return m.Starttls
+	}
+	return ""
+}
+
+func (m *Request) GetSteps() []*Step {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
+}
+
+func (m *Request) GetCookieJar() bool {
+	if m != nil {
+		return m.CookieJar
+	}
+	return false
+}
+
+func (m *Request) GetCookies() []*Cookie {
+	if m != nil {
+		return m.Cookies
+	}
+	return nil
+}
+
+func (m *Request) GetExpectedCookies() []string {
+	if m != nil {
+		return m.ExpectedCookies
+	}
+	return nil
+}
+
+func (m *Request) GetAuthName() string {
+	if m != nil {
+		return m.AuthName
+	}
+	return ""
+}
+
+func (m *Request) GetAuthScheme() string {
+	if m != nil {
+		return m.AuthScheme
+	}
+	return ""
+}
+
+type Cookie struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Cookie) Reset()         { *m = Cookie{} }
+func (m *Cookie) String() string { return proto.CompactTextString(m) }
+func (*Cookie) ProtoMessage()    {}
+func (*Cookie) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{1}
+}
+
+func (m *Cookie) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Cookie.Unmarshal(m, b)
+}
+func (m *Cookie) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Cookie.Marshal(b, m, deterministic)
+}
+func (m *Cookie) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Cookie.Merge(m, src)
+}
+func (m *Cookie) XXX_Size() int {
+	return xxx_messageInfo_Cookie.Size(m)
+}
+func (m *Cookie) XXX_DiscardUnknown() {
+	xxx_messageInfo_Cookie.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Cookie proto.InternalMessageInfo
+
+func (m *Cookie) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Cookie) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type Step struct {
+	Name                 string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Request              *Request      `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	Extract              []*Extraction `protobuf:"bytes,3,rep,name=extract,proto3" json:"extract,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *Step) Reset()         { *m = Step{} }
+func (m *Step) String() string { return proto.CompactTextString(m) }
+func (*Step) ProtoMessage()    {}
+func (*Step) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{2}
+}
+
+func (m *Step) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Step.Unmarshal(m, b)
+}
+func (m *Step) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Step.Marshal(b, m, deterministic)
+}
+func (m *Step) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Step.Merge(m, src)
+}
+func (m *Step) XXX_Size() int {
+	return xxx_messageInfo_Step.Size(m)
+}
+func (m *Step) XXX_DiscardUnknown() {
+	xxx_messageInfo_Step.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Step proto.InternalMessageInfo
+
+func (m *Step) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Step) GetRequest() *Request {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *Step) GetExtract() []*Extraction {
+	if m != nil {
+		return m.Extract
+	}
+	return nil
+}
+
+type Extraction struct {
+	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Source               Extraction_Source `protobuf:"varint,2,opt,name=source,proto3,enum=api.Extraction_Source" json:"source,omitempty"`
+	Expression           string            `protobuf:"bytes,3,opt,name=expression,proto3" json:"expression,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Extraction) Reset()         { *m = Extraction{} }
+func (m *Extraction) String() string { return proto.CompactTextString(m) }
+func (*Extraction) ProtoMessage()    {}
+func (*Extraction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{3}
+}
+
+func (m *Extraction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Extraction.Unmarshal(m, b)
+}
+func (m *Extraction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Extraction.Marshal(b, m, deterministic)
+}
+func (m *Extraction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Extraction.Merge(m, src)
+}
+func (m *Extraction) XXX_Size() int {
+	return xxx_messageInfo_Extraction.Size(m)
+}
+func (m *Extraction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Extraction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Extraction proto.InternalMessageInfo
+
+func (m *Extraction) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Extraction) GetSource() Extraction_Source {
+	if m != nil {
+		return m.Source
+	}
+	return Extraction_HEADER
+}
+
+func (m *Extraction) GetExpression() string {
+	if m != nil {
+		return m.Expression
+	}
+	return ""
+}
+
+type HeaderAssertion struct {
+	Name                 string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Mode                 HeaderAssertion_Mode `protobuf:"varint,2,opt,name=mode,proto3,enum=api.HeaderAssertion_Mode" json:"mode,omitempty"`
+	Value                string               `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *HeaderAssertion) Reset()         { *m = HeaderAssertion{} }
+func (m *HeaderAssertion) String() string { return proto.CompactTextString(m) }
+func (*HeaderAssertion) ProtoMessage()    {}
+func (*HeaderAssertion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{4}
+}
+
+func (m *HeaderAssertion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeaderAssertion.Unmarshal(m, b)
+}
+func (m *HeaderAssertion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeaderAssertion.Marshal(b, m, deterministic)
+}
+func (m *HeaderAssertion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeaderAssertion.Merge(m, src)
+}
+func (m *HeaderAssertion) XXX_Size() int {
+	return xxx_messageInfo_HeaderAssertion.Size(m)
+}
+func (m *HeaderAssertion) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeaderAssertion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeaderAssertion proto.InternalMessageInfo
+
+func (m *HeaderAssertion) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *HeaderAssertion) GetMode() HeaderAssertion_Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return HeaderAssertion_EQUALS
+}
+
+func (m *HeaderAssertion) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type JSONPathAssertion struct {
+	Path                 string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Type                 JSONPathAssertion_Type `protobuf:"varint,2,opt,name=type,proto3,enum=api.JSONPathAssertion_Type" json:"type,omitempty"`
+	Operator             string                 `protobuf:"bytes,3,opt,name=operator,proto3" json:"operator,omitempty"`
+	Value                string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *JSONPathAssertion) Reset()         { *m = JSONPathAssertion{} }
+func (m *JSONPathAssertion) String() string { return proto.CompactTextString(m) }
+func (*JSONPathAssertion) ProtoMessage()    {}
+func (*JSONPathAssertion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{5}
+}
+
+func (m *JSONPathAssertion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JSONPathAssertion.Unmarshal(m, b)
+}
+func (m *JSONPathAssertion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JSONPathAssertion.Marshal(b, m, deterministic)
+}
+func (m *JSONPathAssertion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JSONPathAssertion.Merge(m, src)
+}
+func (m *JSONPathAssertion) XXX_Size() int {
+	return xxx_messageInfo_JSONPathAssertion.Size(m)
+}
+func (m *JSONPathAssertion) XXX_DiscardUnknown() {
+	xxx_messageInfo_JSONPathAssertion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JSONPathAssertion proto.InternalMessageInfo
+
+func (m *JSONPathAssertion) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *JSONPathAssertion) GetType() JSONPathAssertion_Type {
+	if m != nil {
+		return m.Type
+	}
+	return JSONPathAssertion_EQUALS
+}
+
+func (m *JSONPathAssertion) GetOperator() string {
+	if m != nil {
+		return m.Operator
+	}
+	return ""
+}
+
+func (m *JSONPathAssertion) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type SelectorAssertion struct {
+	SelectorType         SelectorAssertion_SelectorType `protobuf:"varint,1,opt,name=selector_type,json=selectorType,proto3,enum=api.SelectorAssertion_SelectorType" json:"selector_type,omitempty"`
+	Selector             string                         `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+	Type                 SelectorAssertion_Type         `protobuf:"varint,3,opt,name=type,proto3,enum=api.SelectorAssertion_Type" json:"type,omitempty"`
+	Attribute            string                         `protobuf:"bytes,4,opt,name=attribute,proto3" json:"attribute,omitempty"`
+	Operator             string                         `protobuf:"bytes,5,opt,name=operator,proto3" json:"operator,omitempty"`
+	Value                string                         `protobuf:"bytes,6,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
+}
+
+func (m *SelectorAssertion) Reset()         { *m = SelectorAssertion{} }
+func (m *SelectorAssertion) String() string { return proto.CompactTextString(m) }
+func (*SelectorAssertion) ProtoMessage()    {}
+func (*SelectorAssertion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{6}
+}
+
+func (m *SelectorAssertion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SelectorAssertion.Unmarshal(m, b)
+}
+func (m *SelectorAssertion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SelectorAssertion.Marshal(b, m, deterministic)
+}
+func (m *SelectorAssertion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SelectorAssertion.Merge(m, src)
+}
+func (m *SelectorAssertion) XXX_Size() int {
+	return xxx_messageInfo_SelectorAssertion.Size(m)
+}
+func (m *SelectorAssertion) XXX_DiscardUnknown() {
+	xxx_messageInfo_SelectorAssertion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SelectorAssertion proto.InternalMessageInfo
+
+func (m *SelectorAssertion) GetSelectorType() SelectorAssertion_SelectorType {
+	if m != nil {
+		return m.SelectorType
+	}
+	return SelectorAssertion_CSS
+}
+
+func (m *SelectorAssertion) GetSelector() string {
+	if m != nil {
+		return m.Selector
+	}
+	return ""
+}
+
+func (m *SelectorAssertion) GetType() SelectorAssertion_Type {
+	if m != nil {
+		return m.Type
+	}
+	return SelectorAssertion_EXISTS
+}
+
+func (m *SelectorAssertion) GetAttribute() string {
+	if m != nil {
+		return m.Attribute
+	}
+	return ""
+}
+
+func (m *SelectorAssertion) GetOperator() string {
+	if m != nil {
+		return m.Operator
+	}
+	return ""
+}
+
+func (m *SelectorAssertion) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type Header struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Header) Reset()         { *m = Header{} }
+func (m *Header) String() string { return proto.CompactTextString(m) }
+func (*Header) ProtoMessage()    {}
+func (*Header) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{7}
+}
+
+func (m *Header) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Header.Unmarshal(m, b)
+}
+func (m *Header) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Header.Marshal(b, m, deterministic)
+}
+func (m *Header) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Header.Merge(m, src)
+}
+func (m *Header) XXX_Size() int {
+	return xxx_messageInfo_Header.Size(m)
+}
+func (m *Header) XXX_DiscardUnknown() {
+	xxx_messageInfo_Header.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Header proto.InternalMessageInfo
+
+func (m *Header) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Header) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type Response struct {
+	Success              bool               `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message              string             `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	DebugMessage         string             `protobuf:"bytes,3,opt,name=debug_message,json=debugMessage,proto3" json:"debug_message,omitempty"`
+	Performance          *Performance       `protobuf:"bytes,4,opt,name=performance,proto3" json:"performance,omitempty"`
+	Status               Status             `protobuf:"varint,5,opt,name=status,proto3,enum=api.Status" json:"status,omitempty"`
+	Assertions           []*AssertionResult `protobuf:"bytes,6,rep,name=assertions,proto3" json:"assertions,omitempty"`
+	FinalUrl             string             `protobuf:"bytes,7,opt,name=final_url,json=finalUrl,proto3" json:"final_url,omitempty"`
+	Redirects            []*Redirect        `protobuf:"bytes,8,rep,name=redirects,proto3" json:"redirects,omitempty"`
+	StatusCode           uint32             `protobuf:"varint,9,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Steps                []*StepResult      `protobuf:"bytes,10,rep,name=steps,proto3" json:"steps,omitempty"`
+	ContentLength        int64              `protobuf:"varint,11,opt,name=content_length,json=contentLength,proto3" json:"content_length,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+func (*Response) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{8}
+}
+
+func (m *Response) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Response.Unmarshal(m, b)
+}
+func (m *Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Response.Marshal(b, m, deterministic)
+}
+func (m *Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Response.Merge(m, src)
+}
+func (m *Response) XXX_Size() int {
+	return xxx_messageInfo_Response.Size(m)
+}
+func (m *Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_Response.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Response proto.InternalMessageInfo
+
+func (m *Response) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *Response) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Response) GetDebugMessage() string {
+	if m != nil {
+		return m.DebugMessage
+	}
+	return ""
+}
+
+func (m *Response) GetPerformance() *Performance {
+	if m != nil {
+		return m.Performance
+	}
+	return nil
+}
+
+func (m *Response) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_OK
+}
+
+func (m *Response) GetAssertions() []*AssertionResult {
+	if m != nil {
+		return m.Assertions
+	}
+	return nil
+}
+
+func (m *Response) GetFinalUrl() string {
+	if m != nil {
+		return m.FinalUrl
+	}
+	return ""
+}
+
+func (m *Response) GetRedirects() []*Redirect {
+	if m != nil {
+		return m.Redirects
+	}
+	return nil
+}
+
+func (m *Response) GetStatusCode() uint32 {
+	if m != nil {
+		return m.StatusCode
+	}
+	return 0
+}
+
+func (m *Response) GetSteps() []*StepResult {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
+}
+
+func (m *Response) GetContentLength() int64 {
+	if m != nil {
+		return m.ContentLength
+	}
+	return 0
+}
+
+type StepResult struct {
+	Name                 string             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status               Status             `protobuf:"varint,2,opt,name=status,proto3,enum=api.Status" json:"status,omitempty"`
+	Message              string             `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Performance          *Performance       `protobuf:"bytes,4,opt,name=performance,proto3" json:"performance,omitempty"`
+	Assertions           []*AssertionResult `protobuf:"bytes,5,rep,name=assertions,proto3" json:"assertions,omitempty"`
+	StatusCode           uint32             `protobuf:"varint,6,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *StepResult) Reset()         { *m = StepResult{} }
+func (m *StepResult) String() string { return proto.CompactTextString(m) }
+func (*StepResult) ProtoMessage()    {}
+func (*StepResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{9}
+}
+
+func (m *StepResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StepResult.Unmarshal(m, b)
+}
+func (m *StepResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StepResult.Marshal(b, m, deterministic)
+}
+func (m *StepResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StepResult.Merge(m, src)
+}
+func (m *StepResult) XXX_Size() int {
+	return xxx_messageInfo_StepResult.Size(m)
+}
+func (m *StepResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_StepResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StepResult proto.InternalMessageInfo
+
+func (m *StepResult) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *StepResult) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_OK
+}
+
+func (m *StepResult) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *StepResult) GetPerformance() *Performance {
+	if m != nil {
+		return m.Performance
+	}
+	return nil
+}
+
+func (m *StepResult) GetAssertions() []*AssertionResult {
+	if m != nil {
+		return m.Assertions
+	}
+	return nil
+}
+
+func (m *StepResult) GetStatusCode() uint32 {
+	if m != nil {
+		return m.StatusCode
+	}
+	return 0
+}
+
+type Redirect struct {
+	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	StatusCode           uint32   `protobuf:"varint,2,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Location             string   `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Redirect) Reset()         { *m = Redirect{} }
+func (m *Redirect) String() string { return proto.CompactTextString(m) }
+func (*Redirect) ProtoMessage()    {}
+func (*Redirect) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{10}
+}
+
+func (m *Redirect) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Redirect.Unmarshal(m, b)
+}
+func (m *Redirect) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Redirect.Marshal(b, m, deterministic)
+}
+func (m *Redirect) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Redirect.Merge(m, src)
+}
+func (m *Redirect) XXX_Size() int {
+	return xxx_messageInfo_Redirect.Size(m)
+}
+func (m *Redirect) XXX_DiscardUnknown() {
+	xxx_messageInfo_Redirect.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Redirect proto.InternalMessageInfo
+
+func (m *Redirect) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Redirect) GetStatusCode() uint32 {
+	if m != nil {
+		return m.StatusCode
+	}
+	return 0
+}
+
+func (m *Redirect) GetLocation() string {
+	if m != nil {
+		return m.Location
+	}
+	return ""
+}
+
+type AssertionResult struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status               Status   `protobuf:"varint,2,opt,name=status,proto3,enum=api.Status" json:"status,omitempty"`
+	Message              string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AssertionResult) Reset()         { *m = AssertionResult{} }
+func (m *AssertionResult) String() string { return proto.CompactTextString(m) }
+func (*AssertionResult) ProtoMessage()    {}
+func (*AssertionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{11}
+}
+
+func (m *AssertionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssertionResult.Unmarshal(m, b)
+}
+func (m *AssertionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssertionResult.Marshal(b, m, deterministic)
+}
+func (m *AssertionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssertionResult.Merge(m, src)
+}
+func (m *AssertionResult) XXX_Size() int {
+	return xxx_messageInfo_AssertionResult.Size(m)
+}
+func (m *AssertionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssertionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssertionResult proto.InternalMessageInfo
+
+func (m *AssertionResult) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AssertionResult) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_OK
+}
+
+func (m *AssertionResult) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type Performance struct {
+	DnsLookupSeconds     float64  `protobuf:"fixed64,1,opt,name=dns_lookup_seconds,json=dnsLookupSeconds,proto3" json:"dns_lookup_seconds,omitempty"`
+	ConnectSeconds       float64  `protobuf:"fixed64,2,opt,name=connect_seconds,json=connectSeconds,proto3" json:"connect_seconds,omitempty"`
+	TlsHandshakeSeconds  float64  `protobuf:"fixed64,3,opt,name=tls_handshake_seconds,json=tlsHandshakeSeconds,proto3" json:"tls_handshake_seconds,omitempty"`
+	FirstByteSeconds     float64  `protobuf:"fixed64,4,opt,name=first_byte_seconds,json=firstByteSeconds,proto3" json:"first_byte_seconds,omitempty"`
+	TotalSeconds         float64  `protobuf:"fixed64,5,opt,name=total_seconds,json=totalSeconds,proto3" json:"total_seconds,omitempty"`
+	BodySizeBytes        int64    `protobuf:"varint,6,opt,name=body_size_bytes,json=bodySizeBytes,proto3" json:"body_size_bytes,omitempty"`
+	HasCertificate       bool     `protobuf:"varint,7,opt,name=has_certificate,json=hasCertificate,proto3" json:"has_certificate,omitempty"`
+	CertExpireDays       float64  `protobuf:"fixed64,8,opt,name=cert_expire_days,json=certExpireDays,proto3" json:"cert_expire_days,omitempty"`
+	CertNotAfterSeconds  int64    `protobuf:"varint,9,opt,name=cert_not_after_seconds,json=certNotAfterSeconds,proto3" json:"cert_not_after_seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Performance) Reset()         { *m = Performance{} }
+func (m *Performance) String() string { return proto.CompactTextString(m) }
+func (*Performance) ProtoMessage()    {}
+func (*Performance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{12}
+}
+
+func (m *Performance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Performance.Unmarshal(m, b)
+}
+func (m *Performance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Performance.Marshal(b, m, deterministic)
+}
+func (m *Performance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Performance.Merge(m, src)
+}
+func (m *Performance) XXX_Size() int {
+	return xxx_messageInfo_Performance.Size(m)
+}
+func (m *Performance) XXX_DiscardUnknown() {
+	xxx_messageInfo_Performance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Performance proto.InternalMessageInfo
+
+func (m *Performance) GetDnsLookupSeconds() float64 {
+	if m != nil {
+		return m.DnsLookupSeconds
+	}
+	return 0
+}
+
+func (m *Performance) GetConnectSeconds() float64 {
+	if m != nil {
+		return m.ConnectSeconds
+	}
+	return 0
+}
+
+func (m *Performance) GetTlsHandshakeSeconds() float64 {
+	if m != nil {
+		return m.TlsHandshakeSeconds
+	}
+	return 0
+}
+
+func (m *Performance) GetFirstByteSeconds() float64 {
+	if m != nil {
+		return m.FirstByteSeconds
+	}
+	return 0
+}
+
+func (m *Performance) GetTotalSeconds() float64 {
+	if m != nil {
+		return m.TotalSeconds
+	}
+	return 0
+}
+
+func (m *Performance) GetBodySizeBytes() int64 {
+	if m != nil {
+		return m.BodySizeBytes
+	}
+	return 0
+}
+
+func (m *Performance) GetHasCertificate() bool {
+	if m != nil {
+		return m.HasCertificate
+	}
+	return false
+}
+
+func (m *Performance) GetCertExpireDays() float64 {
+	if m != nil {
+		return m.CertExpireDays
+	}
+	return 0
+}
+
+func (m *Performance) GetCertNotAfterSeconds() int64 {
+	if m != nil {
+		return m.CertNotAfterSeconds
+	}
+	return 0
+}
+
 func init() {
+	proto.RegisterEnum("api.CheckType", CheckType_name, CheckType_value)
+	proto.RegisterEnum("api.RedirectMode", RedirectMode_name, RedirectMode_value)
+	proto.RegisterEnum("api.Status", Status_name, Status_value)
+	proto.RegisterEnum("api.Extraction_Source", Extraction_Source_name, Extraction_Source_value)
+	proto.RegisterEnum("api.HeaderAssertion_Mode", HeaderAssertion_Mode_name, HeaderAssertion_Mode_value)
+	proto.RegisterEnum("api.JSONPathAssertion_Type", JSONPathAssertion_Type_name, JSONPathAssertion_Type_value)
+	proto.RegisterEnum("api.SelectorAssertion_SelectorType", SelectorAssertion_SelectorType_name, SelectorAssertion_SelectorType_value)
+	proto.RegisterEnum("api.SelectorAssertion_Type", SelectorAssertion_Type_name, SelectorAssertion_Type_value)
 	proto.RegisterType((*Request)(nil), "api.Request")
+	proto.RegisterType((*Cookie)(nil), "api.Cookie")
+	proto.RegisterType((*Step)(nil), "api.Step")
+	proto.RegisterType((*Extraction)(nil), "api.Extraction")
+	proto.RegisterType((*HeaderAssertion)(nil), "api.HeaderAssertion")
+	proto.RegisterType((*JSONPathAssertion)(nil), "api.JSONPathAssertion")
+	proto.RegisterType((*SelectorAssertion)(nil), "api.SelectorAssertion")
+	proto.RegisterType((*Header)(nil), "api.Header")
 	proto.RegisterType((*Response)(nil), "api.Response")
+	proto.RegisterType((*StepResult)(nil), "api.StepResult")
+	proto.RegisterType((*Redirect)(nil), "api.Redirect")
+	proto.RegisterType((*AssertionResult)(nil), "api.AssertionResult")
+	proto.RegisterType((*Performance)(nil), "api.Performance")
 }
 
 func init() { proto.RegisterFile("service.proto", fileDescriptor_a0b84a42fa06f626) }
 
 var fileDescriptor_a0b84a42fa06f626 = []byte{
-	// 345 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x91, 0xcf, 0x4e, 0xe3, 0x30,
-	0x10, 0xc6, 0x37, 0xfd, 0x9b, 0x7a, 0x9b, 0x55, 0xd7, 0xdb, 0x83, 0xd5, 0x53, 0xd4, 0x95, 0x50,
-	0x4e, 0x11, 0x82, 0x1b, 0x47, 0x0a, 0x12, 0x17, 0x2e, 0xee, 0x03, 0x44, 0xae, 0x3d, 0x6a, 0x23,
-	0x68, 0x6d, 0x3c, 0x0e, 0x34, 0x6f, 0xcc, 0x63, 0x20, 0xdb, 0x49, 0x05, 0xb7, 0xf9, 0x7d, 0xdf,
-	0xe4, 0x8b, 0x67, 0x86, 0x64, 0x08, 0xf6, 0xbd, 0x96, 0x50, 0x1a, 0xab, 0x9d, 0xa6, 0x43, 0x61,
-	0xea, 0xf5, 0xe7, 0x80, 0x4c, 0x39, 0xbc, 0x35, 0x80, 0x8e, 0xae, 0x48, 0x1a, 0x1c, 0xa9, 0x5f,
-	0x59, 0x92, 0x27, 0xc5, 0x8c, 0x5f, 0x98, 0x52, 0x32, 0x3a, 0x68, 0x74, 0x6c, 0x10, 0xf4, 0x50,
-	0x7b, 0xcd, 0x08, 0x77, 0x60, 0xc3, 0xa8, 0xf9, 0xda, 0x67, 0x34, 0x08, 0xf6, 0x24, 0x8e, 0xc0,
-	0x46, 0x31, 0xa3, 0xe7, 0x90, 0x2f, 0x10, 0x3f, 0xb4, 0x55, 0x6c, 0xdc, 0xe5, 0x77, 0x4c, 0xaf,
-	0xc9, 0x12, 0xce, 0x06, 0xa4, 0x03, 0x55, 0xa1, 0x13, 0xae, 0xc1, 0x4a, 0x6a, 0x05, 0x6c, 0x92,
-	0x0f, 0x8b, 0x8c, 0xd3, 0xde, 0xdb, 0x06, 0x6b, 0xa3, 0x15, 0xd0, 0xff, 0x24, 0xbb, 0x7c, 0xb1,
-	0xd3, 0xaa, 0x65, 0xd3, 0x10, 0x39, 0xef, 0xc5, 0x7b, 0xad, 0x5a, 0x5a, 0x92, 0x7f, 0x3f, 0x9a,
-	0x2a, 0x0b, 0x7b, 0x38, 0xb3, 0x34, 0xb4, 0xfe, 0xfd, 0xde, 0xca, 0xbd, 0x41, 0x0b, 0xb2, 0x90,
-	0x60, 0x5d, 0x05, 0x67, 0x53, 0x5b, 0xa8, 0x94, 0x68, 0x91, 0xcd, 0xf2, 0xa4, 0xc8, 0xf8, 0x1f,
-	0xaf, 0x3f, 0x06, 0xf9, 0x41, 0xb4, 0x48, 0x97, 0x64, 0xac, 0x60, 0xd7, 0xec, 0x19, 0xc9, 0x93,
-	0x22, 0xe5, 0x11, 0xfc, 0x88, 0xf5, 0x09, 0x41, 0x36, 0x16, 0xd8, 0xef, 0x60, 0x5c, 0x78, 0x2d,
-	0x49, 0xca, 0x01, 0x8d, 0x3e, 0x21, 0x50, 0x46, 0xa6, 0xd8, 0x48, 0x09, 0x88, 0x61, 0xd3, 0x29,
-	0xef, 0xd1, 0x3b, 0x47, 0x40, 0x14, 0x7b, 0xe8, 0x76, 0xdd, 0xa3, 0x1f, 0x38, 0xfc, 0xa4, 0xea,
-	0xfd, 0xb8, 0xf7, 0x79, 0x10, 0x9f, 0xa3, 0x76, 0x73, 0x47, 0x16, 0x4f, 0xce, 0x99, 0xcd, 0x01,
-	0xe4, 0xcb, 0x36, 0x9e, 0x9b, 0x5e, 0x91, 0x71, 0x60, 0x3a, 0x2f, 0x85, 0xa9, 0xcb, 0xee, 0xdc,
-	0xab, 0xac, 0xa3, 0xf8, 0xa4, 0xf5, 0xaf, 0xdd, 0x24, 0x5c, 0xfb, 0xf6, 0x2b, 0x00, 0x00, 0xff,
-	0xff, 0x52, 0xeb, 0xa1, 0x78, 0x28, 0x02, 0x00, 0x00,
+	// 2082 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0x5d, 0x72, 0xdb, 0xc8,
+	0x11, 0x16, 0x09, 0x8a, 0x22, 0x9b, 0x3f, 0x82, 0xc7, 0x92, 0x16, 0xfe, 0xd9, 0xb5, 0x42, 0xc7,
+	0x5e, 0xda, 0x6b, 0x6b, 0x1d, 0x7a, 0xcb, 0x49, 0x25, 0x55, 0x49, 0x68, 0x2e, 0x6d, 0xca, 0xa6,
+	0x48, 0x05, 0xa4, 0x7f, 0xf2, 0x84, 0x1a, 0x03, 0x23, 0x11, 0x6b, 0x10, 0x40, 0x66, 0x86, 0xb6,
+	0xb4, 0x47, 0xc8, 0x15, 0xf2, 0x92, 0xf7, 0xbc, 0xe5, 0x0a, 0xb9, 0x44, 0xce, 0x90, 0x53, 0xa4,
+	0xa6, 0x07, 0x7f, 0x94, 0xb4, 0x55, 0x49, 0xaa, 0xf2, 0x86, 0xf9, 0xfa, 0xeb, 0x46, 0x77, 0x4f,
+	0x4f, 0x4f, 0x03, 0xd0, 0x12, 0x8c, 0x7f, 0xf2, 0x5d, 0x76, 0x10, 0xf3, 0x48, 0x46, 0xc4, 0xa0,
+	0xb1, 0xdf, 0xf9, 0xa7, 0x09, 0x5b, 0x36, 0xfb, 0xd3, 0x8a, 0x09, 0x49, 0x6e, 0x42, 0x0d, 0x25,
+	0x6e, 0x14, 0x58, 0xa5, 0xfd, 0x52, 0xb7, 0x6e, 0x67, 0x6b, 0x42, 0xa0, 0xb2, 0x88, 0x84, 0xb4,
+	0xca, 0x88, 0xe3, 0xb3, 0xc2, 0x62, 0x2a, 0x17, 0x96, 0xa1, 0x31, 0xf5, 0xac, 0x6c, 0xac, 0x04,
+	0xe3, 0x21, 0x5d, 0x32, 0xab, 0xa2, 0x6d, 0xa4, 0x6b, 0xb4, 0x4f, 0x85, 0xf8, 0x1c, 0x71, 0xcf,
+	0xda, 0x4c, 0xec, 0x27, 0x6b, 0xf2, 0x04, 0x76, 0xd8, 0x59, 0xcc, 0x5c, 0xc9, 0x3c, 0x47, 0x48,
+	0x2a, 0x57, 0xc2, 0x71, 0x23, 0x8f, 0x59, 0xd5, 0x7d, 0xa3, 0xdb, 0xb2, 0x49, 0x2a, 0x9b, 0xa1,
+	0x68, 0x10, 0x79, 0x8c, 0xdc, 0x85, 0x56, 0xa6, 0xf1, 0x21, 0xf2, 0xce, 0xad, 0x2d, 0x34, 0xd9,
+	0x4c, 0xc1, 0xe7, 0x91, 0x77, 0x4e, 0x0e, 0xe0, 0xfa, 0x1a, 0xc9, 0xe1, 0xec, 0x94, 0x9d, 0x59,
+	0x35, 0xa4, 0x5e, 0x2b, 0x52, 0x6d, 0x25, 0x20, 0x5d, 0x30, 0x5d, 0xc6, 0xa5, 0xc3, 0xce, 0x62,
+	0x9f, 0x33, 0xc7, 0xa3, 0xe7, 0xc2, 0xaa, 0xef, 0x97, 0xba, 0x2d, 0xbb, 0xad, 0xf0, 0x21, 0xc2,
+	0xdf, 0xd3, 0x73, 0x41, 0x76, 0x60, 0xd3, 0x63, 0x1f, 0x56, 0xa7, 0x16, 0xec, 0x97, 0xba, 0x35,
+	0x5b, 0x2f, 0x54, 0x88, 0x7e, 0x28, 0x98, 0xbb, 0xe2, 0xcc, 0x6a, 0xa0, 0x20, 0x5b, 0x93, 0x1e,
+	0xec, 0x72, 0x26, 0xe2, 0x28, 0x14, 0xcc, 0x91, 0xfe, 0x92, 0x39, 0x9f, 0x29, 0x0f, 0xfd, 0xf0,
+	0xd4, 0x6a, 0xa2, 0x37, 0xd7, 0x53, 0xe1, 0xdc, 0x5f, 0xb2, 0x77, 0x5a, 0x44, 0xbe, 0x83, 0xbd,
+	0x75, 0x1d, 0x97, 0xfb, 0xd2, 0x77, 0x69, 0x60, 0xb5, 0x50, 0x69, 0xa7, 0xa8, 0x34, 0x48, 0x64,
+	0x2a, 0xea, 0x62, 0x14, 0xe9, 0x7b, 0xda, 0x3a, 0xea, 0x3c, 0x90, 0xf4, 0x2d, 0x4f, 0x60, 0xa7,
+	0xc8, 0xcf, 0xde, 0xb1, 0x8d, 0x0a, 0x24, 0x57, 0xc8, 0xde, 0xb0, 0x07, 0xd5, 0x25, 0x93, 0x8b,
+	0xc8, 0xb3, 0x4c, 0xe4, 0x24, 0x2b, 0x72, 0x0f, 0xb6, 0x16, 0x8c, 0x7a, 0x8c, 0x0b, 0xeb, 0xda,
+	0xbe, 0xd1, 0x6d, 0xf4, 0x1a, 0x07, 0x34, 0xf6, 0x0f, 0x46, 0x88, 0xd9, 0xa9, 0x4c, 0x55, 0x0e,
+	0x6e, 0x19, 0xd9, 0x2f, 0x75, 0x9b, 0x36, 0x3e, 0x93, 0xdf, 0x14, 0xf6, 0xf3, 0x07, 0x11, 0x85,
+	0xd6, 0x75, 0x34, 0xb0, 0x87, 0x06, 0x5e, 0xcd, 0xa6, 0x93, 0x63, 0x2a, 0x17, 0x7d, 0x21, 0x18,
+	0x97, 0x7e, 0x14, 0xe6, 0xfb, 0xfc, 0x4a, 0x44, 0x21, 0x19, 0x42, 0x56, 0x22, 0x8e, 0x60, 0x01,
+	0x73, 0x65, 0xc4, 0x85, 0xb5, 0x53, 0xb0, 0x30, 0x4b, 0xd0, 0xdc, 0x42, 0xb6, 0xfd, 0xa9, 0x48,
+	0x90, 0xdf, 0x81, 0x99, 0x99, 0x49, 0xe3, 0xd8, 0x45, 0x23, 0x3b, 0x85, 0x38, 0x72, 0x13, 0xdb,
+	0x29, 0x7b, 0x94, 0x04, 0xf6, 0x0c, 0x5a, 0x9c, 0x79, 0x3e, 0x67, 0xae, 0x74, 0x96, 0xaa, 0x7e,
+	0xf7, 0xf6, 0x4b, 0xdd, 0x76, 0xef, 0x1a, 0x6a, 0xdb, 0x89, 0xe4, 0x28, 0xf2, 0x98, 0xdd, 0xe4,
+	0x85, 0x95, 0x2a, 0xe6, 0x25, 0x3d, 0x73, 0x52, 0x4c, 0x58, 0x5f, 0x60, 0xd1, 0x35, 0x97, 0xf4,
+	0x2c, 0xd5, 0x12, 0xe4, 0x51, 0x21, 0xc8, 0x13, 0x3f, 0xa4, 0x81, 0xb3, 0xe2, 0x81, 0x65, 0xe1,
+	0x06, 0x64, 0x7e, 0xbf, 0x50, 0x82, 0x37, 0x3c, 0x20, 0xdf, 0x40, 0x16, 0xa0, 0x13, 0x44, 0x2e,
+	0x55, 0x0e, 0x5b, 0x37, 0xd6, 0xc9, 0xe3, 0x04, 0x27, 0xcf, 0xe0, 0x8b, 0x8c, 0x9c, 0x05, 0xe0,
+	0x2e, 0xa8, 0x1f, 0x5a, 0x37, 0xf7, 0x8d, 0x6e, 0xdd, 0xde, 0x4d, 0xc5, 0xa9, 0x3b, 0x03, 0x25,
+	0xc4, 0xf3, 0x12, 0xf8, 0x2c, 0x94, 0x0e, 0x16, 0x10, 0x1e, 0xfb, 0x5b, 0xf8, 0x8e, 0xb6, 0xc6,
+	0x07, 0x8c, 0xcb, 0x89, 0x3a, 0xfc, 0x17, 0x98, 0x27, 0x7e, 0xc0, 0xac, 0xdb, 0x17, 0x99, 0x2f,
+	0xfc, 0x80, 0x91, 0xfb, 0xb0, 0x9d, 0x30, 0x3f, 0xb2, 0x73, 0x4d, 0xfc, 0x12, 0x89, 0x2d, 0x0d,
+	0xbf, 0x66, 0xe7, 0xc8, 0xeb, 0x82, 0x29, 0xf9, 0x4a, 0x48, 0x47, 0xc8, 0x88, 0x33, 0xfd, 0xee,
+	0xaf, 0xb4, 0x45, 0xc4, 0x67, 0x0a, 0xc6, 0x77, 0xef, 0x42, 0xd5, 0xa5, 0x4e, 0xcc, 0x96, 0xd6,
+	0x1d, 0x2c, 0xb8, 0x4d, 0x97, 0x1e, 0xb3, 0xe5, 0x5a, 0x07, 0x89, 0xfd, 0x50, 0x58, 0xfb, 0x18,
+	0x6a, 0x56, 0x59, 0xc7, 0x7e, 0x28, 0x94, 0x37, 0x4b, 0x3f, 0x74, 0x64, 0x20, 0x9c, 0x4f, 0x8c,
+	0x0b, 0x95, 0xc4, 0x9f, 0x69, 0x6f, 0x96, 0x7e, 0x38, 0x0f, 0xc4, 0x5b, 0x0d, 0xaa, 0xd3, 0x4d,
+	0x83, 0x20, 0xfa, 0xcc, 0x3c, 0xc7, 0xf5, 0xe3, 0x05, 0xe3, 0x8e, 0x58, 0xf9, 0x92, 0x09, 0xab,
+	0x83, 0x46, 0xaf, 0x27, 0xc2, 0x01, 0xca, 0x66, 0x28, 0x22, 0x4f, 0x61, 0x4f, 0xbf, 0xcb, 0x51,
+	0xfd, 0x54, 0x05, 0xe0, 0xf8, 0xa1, 0x23, 0x68, 0x68, 0xdd, 0xc5, 0xde, 0x91, 0xf4, 0xae, 0x51,
+	0x22, 0x3c, 0x0c, 0x67, 0x34, 0x5c, 0xf3, 0x5a, 0xd0, 0x50, 0x58, 0x3f, 0x5f, 0xf7, 0x7a, 0x46,
+	0x43, 0x41, 0x7e, 0x0b, 0xb7, 0xfc, 0x50, 0x32, 0xbe, 0x64, 0x9e, 0x4f, 0x25, 0xbb, 0xd8, 0x09,
+	0xee, 0x61, 0x04, 0x37, 0x8a, 0x94, 0xf5, 0x8e, 0xf0, 0x7b, 0xb8, 0x7d, 0x95, 0x7e, 0xd6, 0x19,
+	0xee, 0xa3, 0x81, 0x9b, 0x97, 0x0d, 0x64, 0x1d, 0xa2, 0x58, 0xac, 0xbe, 0x10, 0x2b, 0xc6, 0x1d,
+	0x37, 0xb4, 0xbe, 0x5e, 0xaf, 0xbf, 0x43, 0x14, 0x0c, 0xc2, 0xb5, 0x3e, 0x9d, 0xb0, 0x23, 0x7e,
+	0x6a, 0x75, 0xd7, 0xfb, 0xb4, 0xa6, 0x4f, 0xf9, 0x69, 0xce, 0x77, 0x22, 0x57, 0xc4, 0xea, 0xc6,
+	0x88, 0x03, 0xe6, 0x59, 0x0f, 0x30, 0x6d, 0x09, 0x7f, 0xea, 0x8a, 0x78, 0xa6, 0x05, 0xaa, 0xbe,
+	0xfd, 0xd0, 0x0d, 0x56, 0x1e, 0x53, 0xbb, 0xe8, 0x9f, 0xf8, 0x6a, 0x9b, 0x54, 0x05, 0x0b, 0xeb,
+	0x21, 0xea, 0xec, 0x26, 0xe2, 0xb7, 0x89, 0x14, 0xcb, 0x5b, 0x90, 0xc7, 0x00, 0xee, 0x82, 0xb9,
+	0x1f, 0x1d, 0x79, 0x1e, 0x33, 0xeb, 0x1b, 0x3c, 0xcc, 0x6d, 0x3c, 0xcc, 0x03, 0x05, 0xcf, 0xcf,
+	0x63, 0x66, 0xd7, 0xdd, 0xf4, 0x91, 0xdc, 0x81, 0x86, 0xba, 0x63, 0x19, 0xd7, 0xd5, 0xf8, 0x08,
+	0xdd, 0x07, 0x0d, 0x4d, 0x92, 0x2b, 0x50, 0x48, 0xca, 0xa5, 0x0c, 0x84, 0xf5, 0x58, 0x5f, 0x81,
+	0xe9, 0x9a, 0xdc, 0x81, 0x4d, 0x21, 0x59, 0x2c, 0xac, 0x03, 0xec, 0x38, 0x75, 0xdd, 0xb6, 0x24,
+	0x8b, 0x6d, 0x8d, 0x93, 0x2f, 0x01, 0xdc, 0x28, 0xfa, 0xe8, 0x33, 0xe7, 0x07, 0xca, 0xad, 0x6f,
+	0xd1, 0xef, 0xba, 0x46, 0x5e, 0x51, 0xae, 0x7a, 0xaf, 0x5e, 0x08, 0xeb, 0x49, 0xa1, 0xf7, 0x0e,
+	0x10, 0xb3, 0x53, 0x19, 0x79, 0x50, 0xe8, 0x71, 0x29, 0xff, 0x17, 0x58, 0x42, 0x59, 0x37, 0x1b,
+	0x24, 0xd4, 0x5b, 0x50, 0xa7, 0x2b, 0xb9, 0xd0, 0xc1, 0xf4, 0xb4, 0xbb, 0x0a, 0xc0, 0x50, 0xee,
+	0x40, 0x03, 0x85, 0xc2, 0x5d, 0xb0, 0x25, 0xb3, 0x9e, 0xea, 0x58, 0x15, 0x34, 0x43, 0xa4, 0xd3,
+	0x83, 0xaa, 0x36, 0xa4, 0xda, 0x3d, 0x9a, 0xd0, 0x43, 0x05, 0x3e, 0xab, 0xfb, 0xf3, 0x13, 0x0d,
+	0x56, 0x2c, 0x99, 0x28, 0xf4, 0xa2, 0xb3, 0x84, 0x8a, 0x8a, 0xf8, 0x4a, 0x8d, 0xfb, 0xb0, 0xc5,
+	0xf5, 0xa4, 0x82, 0x3a, 0x8d, 0x5e, 0x33, 0xe9, 0xaa, 0x88, 0xd9, 0xa9, 0x90, 0x3c, 0x80, 0x2d,
+	0x76, 0x26, 0x39, 0x75, 0xa5, 0x65, 0x60, 0x1e, 0xb6, 0x91, 0x37, 0xd4, 0x98, 0x6a, 0xdb, 0xa9,
+	0xbc, 0xf3, 0xd7, 0x12, 0x40, 0x8e, 0x5f, 0xf9, 0xd6, 0x03, 0xa8, 0x8a, 0x68, 0xc5, 0x5d, 0xed,
+	0x68, 0x3b, 0xb9, 0x4d, 0x72, 0xa5, 0x83, 0x19, 0x4a, 0xed, 0x84, 0x45, 0xbe, 0x02, 0x60, 0x67,
+	0x31, 0x67, 0x02, 0x5b, 0x85, 0x1e, 0x8d, 0x0a, 0x48, 0xe7, 0x00, 0xaa, 0x5a, 0x83, 0x00, 0x54,
+	0x47, 0xc3, 0xfe, 0xf7, 0x43, 0xdb, 0xdc, 0x20, 0x75, 0xd8, 0xb4, 0x87, 0x2f, 0x87, 0xef, 0xcd,
+	0x12, 0x69, 0x41, 0x5d, 0xdd, 0x76, 0xce, 0x71, 0x7f, 0x3e, 0x32, 0xcb, 0x9d, 0xbf, 0x97, 0x60,
+	0xfb, 0xc2, 0xb5, 0x73, 0xa5, 0x9f, 0x8f, 0xa1, 0x82, 0x17, 0x8e, 0xf6, 0xf2, 0xc6, 0x55, 0xd7,
+	0xd5, 0x01, 0x5e, 0x3c, 0x48, 0xcb, 0xd3, 0x6f, 0x14, 0xd3, 0xff, 0x02, 0x2a, 0x8a, 0xa3, 0x5c,
+	0x1b, 0xfe, 0xe1, 0x4d, 0x7f, 0x3c, 0x33, 0x37, 0x48, 0x03, 0xb6, 0x8e, 0xfa, 0xf3, 0xc1, 0x68,
+	0x38, 0x33, 0x4b, 0xa4, 0x09, 0xb5, 0xc1, 0x74, 0x32, 0xef, 0x1f, 0x4e, 0x66, 0x66, 0x59, 0x89,
+	0x8e, 0xed, 0xe1, 0x6c, 0x38, 0x99, 0x9b, 0x86, 0xd2, 0xe9, 0x3f, 0xc7, 0xe7, 0x4a, 0xe7, 0x1f,
+	0x25, 0xb8, 0x76, 0xe9, 0xca, 0xce, 0xe6, 0xc5, 0x52, 0x61, 0x5e, 0xfc, 0x16, 0x2a, 0x78, 0xb4,
+	0xb4, 0xdb, 0xb7, 0xae, 0xbe, 0xec, 0x0f, 0xf0, 0x9c, 0x21, 0x51, 0x9d, 0xa0, 0x28, 0x66, 0x9c,
+	0xca, 0x88, 0x27, 0xbe, 0x67, 0xeb, 0x3c, 0xa8, 0x4a, 0x31, 0xa8, 0x5f, 0x42, 0x05, 0x0f, 0x67,
+	0x31, 0x28, 0xf5, 0xfc, 0xfe, 0x70, 0x36, 0x57, 0x31, 0x35, 0x60, 0x6b, 0x30, 0x3d, 0x3a, 0xee,
+	0xdb, 0x43, 0xb3, 0xac, 0x04, 0xe3, 0xe1, 0xe4, 0xe5, 0x7c, 0x64, 0x1a, 0x9d, 0x3f, 0x1b, 0x70,
+	0xed, 0xd2, 0xd8, 0x40, 0x46, 0x6a, 0x8e, 0xd6, 0xa0, 0xee, 0x0a, 0x25, 0x74, 0xfd, 0xee, 0xd5,
+	0x53, 0x46, 0x86, 0x60, 0x08, 0x4d, 0x51, 0x58, 0x61, 0x33, 0x48, 0xd6, 0xc9, 0x29, 0xc8, 0xd6,
+	0x59, 0x5e, 0x8c, 0x42, 0x5e, 0x2e, 0x1b, 0x2f, 0xe4, 0xe5, 0x36, 0xd4, 0xa9, 0x94, 0xdc, 0xff,
+	0xb0, 0x92, 0x69, 0xfc, 0x39, 0xb0, 0x96, 0xb5, 0xcd, 0x9f, 0xca, 0x5a, 0xb5, 0x98, 0xb5, 0x67,
+	0xd0, 0x2c, 0xba, 0x4e, 0xb6, 0xc0, 0x18, 0xcc, 0x54, 0xea, 0xda, 0x00, 0xef, 0x55, 0x6d, 0x3a,
+	0xa3, 0xf9, 0xd1, 0x58, 0xd7, 0xab, 0x5e, 0xbf, 0x3f, 0x1a, 0x9b, 0xe5, 0xce, 0xdb, 0x42, 0xb6,
+	0x75, 0x86, 0x37, 0xc8, 0x36, 0x34, 0xe6, 0xc3, 0xf7, 0x73, 0x27, 0x49, 0x7f, 0x89, 0x98, 0xd0,
+	0x44, 0x20, 0x2d, 0xac, 0x32, 0xd9, 0x01, 0xb3, 0x3f, 0x9f, 0xdb, 0x87, 0xcf, 0xdf, 0xcc, 0x87,
+	0x29, 0xcf, 0x50, 0xc7, 0x62, 0x30, 0x7d, 0x83, 0x25, 0xd5, 0x83, 0xaa, 0x2e, 0xe7, 0xff, 0xa2,
+	0x9b, 0xfc, 0xcd, 0x80, 0x9a, 0x9d, 0x0c, 0xc8, 0xc4, 0x82, 0x2d, 0xb1, 0x72, 0x5d, 0x26, 0x04,
+	0x6a, 0xd6, 0xec, 0x74, 0xa9, 0x24, 0x4b, 0x26, 0x04, 0x3d, 0x4d, 0xd5, 0xd3, 0xa5, 0xba, 0x6b,
+	0x71, 0xae, 0x77, 0x52, 0xb9, 0xae, 0xb8, 0x26, 0x82, 0x47, 0x09, 0xa9, 0x07, 0x8d, 0x98, 0xf1,
+	0x93, 0x88, 0x2f, 0x69, 0xe8, 0xea, 0xdc, 0x37, 0x7a, 0x26, 0xee, 0xd8, 0x71, 0x8e, 0xdb, 0x45,
+	0x12, 0xb9, 0x0b, 0x55, 0xfd, 0x95, 0x83, 0xbb, 0xd1, 0x4e, 0x5a, 0xb5, 0xfe, 0xba, 0xb1, 0x13,
+	0x11, 0xf9, 0x0e, 0x80, 0xa6, 0x5b, 0x2d, 0xac, 0x6a, 0x61, 0x0e, 0xcd, 0x27, 0x50, 0x26, 0x56,
+	0x81, 0xb4, 0x0b, 0x3c, 0xd5, 0xb4, 0xf3, 0xe1, 0x50, 0x7f, 0x13, 0xd5, 0x4e, 0xf2, 0xa1, 0xb0,
+	0x9e, 0xcf, 0x98, 0x35, 0xb4, 0xd8, 0x5a, 0x9b, 0x4d, 0xed, 0x5c, 0x8e, 0xb7, 0x59, 0xe1, 0x53,
+	0x4c, 0x7f, 0x07, 0x81, 0xc8, 0x3f, 0xc1, 0xee, 0xa5, 0x37, 0x16, 0x14, 0xfa, 0x2c, 0xde, 0x58,
+	0xda, 0x2d, 0x2d, 0x25, 0xf7, 0xa0, 0xed, 0x46, 0xa1, 0x54, 0x13, 0x5d, 0xc0, 0xc2, 0x53, 0xb9,
+	0xc0, 0x4f, 0x23, 0xc3, 0x6e, 0x25, 0xe8, 0x18, 0xc1, 0xce, 0xbf, 0x4a, 0x00, 0xb9, 0xf2, 0x95,
+	0xdb, 0x9c, 0xa7, 0xad, 0xfc, 0xd3, 0x69, 0x2b, 0x6c, 0xa7, 0xb1, 0xbe, 0x9d, 0xff, 0xcb, 0x4e,
+	0xad, 0x6f, 0xc2, 0xe6, 0x7f, 0xb8, 0x09, 0x17, 0x52, 0x57, 0xbd, 0x98, 0xba, 0xce, 0x1f, 0x55,
+	0x65, 0xea, 0x44, 0x13, 0x13, 0x0c, 0xb5, 0x57, 0x3a, 0x50, 0xf5, 0x78, 0x51, 0xbd, 0x7c, 0x29,
+	0xf3, 0x37, 0xa1, 0x96, 0xcd, 0xf4, 0x49, 0x17, 0x4c, 0xd7, 0x1d, 0x0f, 0xb6, 0x2f, 0xb8, 0xf6,
+	0x7f, 0xc8, 0x65, 0xe7, 0x2f, 0x06, 0x34, 0x0a, 0x49, 0x53, 0xf3, 0x9e, 0x17, 0x0a, 0x27, 0x88,
+	0xa2, 0x8f, 0xab, 0xd8, 0x11, 0xcc, 0x8d, 0x42, 0x4f, 0x9f, 0xb4, 0x92, 0x6d, 0x7a, 0xa1, 0x18,
+	0xa3, 0x60, 0xa6, 0x71, 0xf2, 0x35, 0x6c, 0xbb, 0x51, 0x18, 0xaa, 0x01, 0x2e, 0xa5, 0x96, 0x91,
+	0xda, 0x4e, 0xe0, 0x94, 0xd8, 0x83, 0x5d, 0x35, 0x7a, 0x2f, 0x68, 0xe8, 0x89, 0x05, 0xfd, 0xc8,
+	0x32, 0xba, 0x81, 0xf4, 0xeb, 0x32, 0x10, 0xa3, 0x54, 0x96, 0xea, 0x3c, 0x02, 0x72, 0xe2, 0x73,
+	0x21, 0x9d, 0x0f, 0xe7, 0x32, 0x57, 0xa8, 0x68, 0x57, 0x50, 0xf2, 0xfc, 0x5c, 0x66, 0xec, 0xbb,
+	0xd0, 0x92, 0x91, 0xa4, 0x41, 0x46, 0xdc, 0x44, 0x62, 0x13, 0xc1, 0x94, 0x74, 0x1f, 0xb6, 0xf1,
+	0xf7, 0x81, 0xf0, 0x7f, 0x64, 0x68, 0x56, 0xe0, 0x9e, 0x1a, 0x76, 0x4b, 0xc1, 0x33, 0xff, 0x47,
+	0xa6, 0x4c, 0x62, 0x5c, 0x0b, 0x2a, 0xf0, 0x13, 0xc7, 0x3f, 0xf1, 0x5d, 0x2a, 0x19, 0x1e, 0xc1,
+	0x9a, 0xdd, 0x5e, 0x50, 0x31, 0xc8, 0xd1, 0x2b, 0x7f, 0x34, 0xd4, 0x92, 0x0c, 0xac, 0xff, 0x68,
+	0x78, 0x0a, 0x7b, 0xc8, 0x0c, 0x23, 0xe9, 0xd0, 0x13, 0xa9, 0x3e, 0x2c, 0x12, 0x47, 0xeb, 0xe8,
+	0x01, 0x7e, 0xea, 0x4f, 0x22, 0xd9, 0x57, 0xb2, 0xc4, 0xdf, 0x87, 0x8f, 0xa0, 0x9e, 0x0d, 0xa8,
+	0xa4, 0x06, 0x95, 0xd1, 0x7c, 0x7e, 0x6c, 0x6e, 0xa8, 0x26, 0x3e, 0x1f, 0x27, 0xf7, 0xf8, 0x6c,
+	0x30, 0x9c, 0xf4, 0xed, 0xc3, 0xa9, 0x59, 0x7e, 0xf8, 0x00, 0x9a, 0xc5, 0x6f, 0x53, 0xd5, 0xbb,
+	0x5f, 0x4c, 0xc7, 0xe3, 0xe9, 0x3b, 0x73, 0x43, 0xb5, 0xf7, 0xc9, 0xd4, 0x49, 0x96, 0xa5, 0x87,
+	0xbf, 0x82, 0xaa, 0x2e, 0x11, 0x52, 0x85, 0xf2, 0xf4, 0xb5, 0x9e, 0x0f, 0xde, 0xf5, 0xed, 0xc9,
+	0xe1, 0xe4, 0x65, 0x32, 0x1f, 0xd8, 0x87, 0xf3, 0xc3, 0x41, 0x7f, 0xac, 0xe7, 0x83, 0x37, 0x93,
+	0xd7, 0x93, 0xe9, 0xbb, 0x89, 0x69, 0xf4, 0x7e, 0x0d, 0xe6, 0x48, 0xca, 0x18, 0xdd, 0x9a, 0xe9,
+	0x1f, 0x51, 0xe4, 0x3e, 0x6c, 0xe2, 0x9a, 0xac, 0x8d, 0x72, 0x37, 0xd3, 0x96, 0xa4, 0x3b, 0x77,
+	0x67, 0xe3, 0x43, 0x15, 0xff, 0x43, 0x3d, 0xfd, 0xf7, 0x00, 0x86, 0x37, 0x6e, 0x04, 0xc2, 0x12,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
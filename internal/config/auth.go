@@ -0,0 +1,372 @@
+package config
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Auth types
+const (
+	AuthOAuth2 = "oauth2"
+	AuthBearer = "bearer"
+	AuthAPIKey = "api_key"
+	AuthBasic  = "basic"
+)
+
+// AuthEnvPrefix is the prefix of environment variables defining auth,
+// e.g. HTTP_CHECK_AUTH_SHOP_PASSWORD sets the password of auth "shop"
+const AuthEnvPrefix = "HTTP_CHECK_AUTH_"
+
+// authEnvFields maps the suffix of an environment variable to the field it sets
+var authEnvFields = []struct {
+	suffix string
+	set    func(a *Auth, v string)
+}{
+	{"_USERNAME", func(a *Auth, v string) { a.Username = v }},
+	{"_PASSWORD", func(a *Auth, v string) { a.Password = v }},
+	{"_TOKEN", func(a *Auth, v string) { a.Token = v }},
+	{"_CERT_FILE", func(a *Auth, v string) { a.CertFile = v }},
+	{"_KEY_FILE", func(a *Auth, v string) { a.KeyFile = v }},
+	{"_ALLOWED_HOSTS", func(a *Auth, v string) { a.AllowedHosts = splitHosts(v) }},
+}
+
+// Auth defines how requests are authenticated against a target
+type Auth struct {
+	// Type is one of oauth2, bearer, api_key or basic. It can be omitted if only a client certificate is used.
+	Type string `yaml:"type"`
+
+	// AllowedHosts are the only hosts the auth is used for, so secrets are never sent to hosts chosen by a client.
+	// A host matches any port unless a port is given (e.g. api.example.com:8443), *.example.com matches all sub domains.
+	AllowedHosts []string `yaml:"allowed_hosts"`
+
+	// OAuth2 client credentials
+	TokenURL     string   `yaml:"token_url"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	Scopes       []string `yaml:"scopes"`
+
+	// Token is sent as bearer token
+	Token string `yaml:"token"`
+
+	// Key is sent in the header (API key)
+	Header string `yaml:"header"`
+	Key    string `yaml:"key"`
+
+	// Username and password are sent using the auth scheme of the request (basic, digest or ntlm)
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// Client certificate presented to the target, can be combined with any type
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+// LoadAuthFile reads auth from a YAML file mapping names to auth
+func LoadAuthFile(path string) (map[string]*Auth, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read auth file")
+	}
+
+	auth := make(map[string]*Auth)
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+
+	err = dec.Decode(&auth)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "Could not parse auth file")
+	}
+
+	return auth, nil
+}
+
+// LoadAuthDir reads auth from a secrets directory. Each sub directory is an auth named by the directory.
+// The files username, password, token, tls.crt and tls.key are used (compatible with Kubernetes secrets of type basic-auth and tls).
+// The file allowed_hosts contains the allowed hosts separated by new lines or commas.
+func LoadAuthDir(dir string) (map[string]*Auth, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read auth directory")
+	}
+
+	auth := make(map[string]*Auth)
+	for _, e := range entries {
+		// mounted secrets are symlinks to hidden directories (e.g. ..data)
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		p := filepath.Join(dir, e.Name())
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Auth %s", e.Name())
+		}
+
+		if !fi.IsDir() {
+			continue
+		}
+
+		a, err := loadAuthDir(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Auth %s", e.Name())
+		}
+
+		auth[e.Name()] = a
+	}
+
+	return auth, nil
+}
+
+func loadAuthDir(dir string) (*Auth, error) {
+	a := &Auth{}
+
+	for name, v := range map[string]*string{
+		"username": &a.Username,
+		"password": &a.Password,
+		"token":    &a.Token,
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		*v = strings.TrimRight(string(b), "\r\n")
+	}
+
+	for name, v := range map[string]*string{
+		"tls.crt": &a.CertFile,
+		"tls.key": &a.KeyFile,
+	} {
+		p := filepath.Join(dir, name)
+		_, err := os.Stat(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		*v = p
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "allowed_hosts"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	a.AllowedHosts = splitHosts(string(b))
+
+	a.setDefaultType()
+
+	return a, nil
+}
+
+// AuthFromEnv reads auth from environment variables (format KEY=value) starting with AuthEnvPrefix.
+// The name is the lower case part between the prefix and the field name (USERNAME, PASSWORD, TOKEN, CERT_FILE, KEY_FILE
+// or ALLOWED_HOSTS separated by commas).
+func AuthFromEnv(environ []string) map[string]*Auth {
+	auth := make(map[string]*Auth)
+
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, AuthEnvPrefix) {
+			continue
+		}
+
+		kv = strings.TrimPrefix(kv, AuthEnvPrefix)
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			continue
+		}
+		key, value := kv[:i], kv[i+1:]
+
+		for _, f := range authEnvFields {
+			if !strings.HasSuffix(key, f.suffix) || len(key) == len(f.suffix) {
+				continue
+			}
+
+			name := strings.ToLower(strings.TrimSuffix(key, f.suffix))
+			if auth[name] == nil {
+				auth[name] = &Auth{}
+			}
+			f.set(auth[name], value)
+			break
+		}
+	}
+
+	for _, a := range auth {
+		a.setDefaultType()
+	}
+
+	return auth
+}
+
+// AddAuth validates auth and adds them to the configuration. Names have to be unique across all sources.
+func (c *Config) AddAuth(auth map[string]*Auth) error {
+	if c.Auth == nil {
+		c.Auth = make(map[string]*Auth, len(auth))
+	}
+
+	names := make([]string, 0, len(auth))
+	for name := range auth {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, found := c.Auth[name]; found {
+			return fmt.Errorf("Auth %s is defined more than once", name)
+		}
+
+		a := auth[name]
+		if a == nil {
+			return fmt.Errorf("Auth %s: definition is empty", name)
+		}
+
+		err := a.validate()
+		if err != nil {
+			return errors.Wrapf(err, "Auth %s", name)
+		}
+
+		c.Auth[name] = a
+	}
+
+	return nil
+}
+
+// AllowsHost returns if the auth may be used for the host (format host:port)
+func (a *Auth) AllowsHost(address string) bool {
+	host, port := splitHostPort(address)
+
+	for _, allowed := range a.AllowedHosts {
+		h, p := splitHostPort(allowed)
+		if len(p) > 0 && p != port {
+			continue
+		}
+
+		if h == host || (strings.HasPrefix(h, "*.") && strings.HasSuffix(host, h[1:])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitHostPort returns the normalized host name and the port (empty if none)
+func splitHostPort(address string) (string, string) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = strings.Trim(address, "[]"), ""
+	}
+
+	return strings.TrimSuffix(strings.ToLower(host), "."), port
+}
+
+func splitHosts(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// IsAuthFile returns if the file is the certificate or key of an auth. Files are compared by identity,
+// so other paths to the file (e.g. symlinks) match as well.
+func (c *Config) IsAuthFile(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	for _, a := range c.Auth {
+		for _, f := range []string{a.CertFile, a.KeyFile} {
+			if len(f) == 0 {
+				continue
+			}
+
+			afi, err := os.Stat(f)
+			if err == nil && os.SameFile(fi, afi) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// HasHTTPAuth returns if the auth authenticates HTTP requests (in contrast to a client certificate only)
+func (a *Auth) HasHTTPAuth() bool {
+	return len(a.Type) > 0
+}
+
+// setDefaultType derives the type of auth loaded from a secrets directory or the environment
+func (a *Auth) setDefaultType() {
+	switch {
+	case len(a.Username) > 0 && len(a.Token) == 0:
+		a.Type = AuthBasic
+	case len(a.Token) > 0 && len(a.Username) == 0:
+		a.Type = AuthBearer
+	}
+}
+
+func (a *Auth) validate() error {
+	if len(a.AllowedHosts) == 0 {
+		return fmt.Errorf("allowed_hosts is required")
+	}
+
+	for _, h := range a.AllowedHosts {
+		name, _ := splitHostPort(h)
+		if len(name) == 0 || strings.Contains(strings.TrimPrefix(name, "*."), "*") {
+			return fmt.Errorf("Invalid allowed host '%s'", h)
+		}
+	}
+
+	if (len(a.CertFile) > 0) != (len(a.KeyFile) > 0) {
+		return fmt.Errorf("cert_file and key_file have to be specified together")
+	}
+
+	switch a.Type {
+	case AuthOAuth2:
+		if len(a.TokenURL) == 0 || len(a.ClientID) == 0 || len(a.ClientSecret) == 0 {
+			return fmt.Errorf("token_url, client_id and client_secret are required")
+		}
+	case AuthBearer:
+		if len(a.Token) == 0 {
+			return fmt.Errorf("token is required")
+		}
+	case AuthAPIKey:
+		if len(a.Header) == 0 || len(a.Key) == 0 {
+			return fmt.Errorf("header and key are required")
+		}
+	case AuthBasic:
+		if len(a.Username) == 0 {
+			return fmt.Errorf("username is required")
+		}
+	case "":
+		if len(a.CertFile) == 0 {
+			return fmt.Errorf("type or cert_file is required")
+		}
+	default:
+		return fmt.Errorf("Unsupported type '%s' (expected %s, %s, %s or %s)", a.Type, AuthOAuth2, AuthBearer, AuthAPIKey, AuthBasic)
+	}
+
+	if len(a.CertFile) > 0 {
+		_, err := tls.LoadX509KeyPair(a.CertFile, a.KeyFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadAuthFile(t *testing.T) {
+	auth, err := LoadAuthFile(writeConfig(t, `
+shop:
+  type: basic
+  allowed_hosts: [shop.mauve.de]
+  username: monitoring
+  password: s3cr3t
+api:
+  type: bearer
+  allowed_hosts: [api.mauve.de]
+  token: t0k3n
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, &Auth{Type: AuthBasic, AllowedHosts: []string{"shop.mauve.de"}, Username: "monitoring", Password: "s3cr3t"}, auth["shop"])
+	assert.Equal(t, &Auth{Type: AuthBearer, AllowedHosts: []string{"api.mauve.de"}, Token: "t0k3n"}, auth["api"])
+
+	_, err = LoadAuthFile(writeConfig(t, "shop:\n  user: monitoring\n"))
+	assert.Error(t, err, "unknown field")
+}
+
+func TestLoadAuthDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		p := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(p), 0700)
+		if err == nil {
+			err = ioutil.WriteFile(p, []byte(content), 0600)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("shop/username", "monitoring\n")
+	write("shop/password", "s3cr3t\n")
+	write("shop/allowed_hosts", "shop.mauve.de\n*.shop.mauve.de\n")
+	write("api/token", "t0k3n")
+	write("api/allowed_hosts", "api.mauve.de")
+	write("..data/token", "ignored")
+	write("README", "ignored")
+
+	auth, err := LoadAuthDir(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, auth, 2)
+	assert.Equal(t, &Auth{Type: AuthBasic, AllowedHosts: []string{"shop.mauve.de", "*.shop.mauve.de"}, Username: "monitoring", Password: "s3cr3t"}, auth["shop"])
+	assert.Equal(t, &Auth{Type: AuthBearer, AllowedHosts: []string{"api.mauve.de"}, Token: "t0k3n"}, auth["api"])
+}
+
+func TestAuthFromEnv(t *testing.T) {
+	auth := AuthFromEnv([]string{
+		"HTTP_CHECK_AUTH_SHOP_PROD_USERNAME=monitoring",
+		"HTTP_CHECK_AUTH_SHOP_PROD_PASSWORD=s3cr3t=",
+		"HTTP_CHECK_AUTH_SHOP_PROD_ALLOWED_HOSTS=shop.mauve.de, *.shop.mauve.de",
+		"HTTP_CHECK_AUTH_API_TOKEN=t0k3n",
+		"HTTP_CHECK_AUTH_MTLS_CERT_FILE=cert.pem",
+		"HTTP_CHECK_AUTH_TOKEN=ignored",
+		"HOME=/root",
+	})
+
+	assert.Len(t, auth, 3)
+	assert.Equal(t, &Auth{Type: AuthBasic, AllowedHosts: []string{"shop.mauve.de", "*.shop.mauve.de"}, Username: "monitoring", Password: "s3cr3t="}, auth["shop_prod"])
+	assert.Equal(t, &Auth{Type: AuthBearer, Token: "t0k3n"}, auth["api"])
+	assert.Equal(t, &Auth{CertFile: "cert.pem"}, auth["mtls"])
+}
+
+func TestAddAuth(t *testing.T) {
+	cfg := &Config{}
+
+	hosts := []string{"api.mauve.de"}
+	err := cfg.AddAuth(map[string]*Auth{"api": {Type: AuthBearer, AllowedHosts: hosts, Token: "t0k3n"}})
+	assert.NoError(t, err)
+
+	err = cfg.AddAuth(map[string]*Auth{"api": {Type: AuthBearer, AllowedHosts: hosts, Token: "other"}})
+	assert.EqualError(t, err, "Auth api is defined more than once")
+
+	invalid := []*Auth{
+		{AllowedHosts: hosts},
+		{AllowedHosts: hosts, Password: "s3cr3t"},
+		{AllowedHosts: hosts, Username: "monitoring", Token: "t0k3n"},
+		{AllowedHosts: hosts, Type: AuthBasic, Username: "monitoring", CertFile: "cert.pem"},
+		{AllowedHosts: hosts, CertFile: "/nonexistent/cert.pem", KeyFile: "/nonexistent/key.pem"},
+		{Type: AuthBearer, Token: "t0k3n"},
+		{Type: AuthBearer, AllowedHosts: []string{"*"}, Token: "t0k3n"},
+		{Type: AuthBearer, AllowedHosts: []string{"api.*.de"}, Token: "t0k3n"},
+	}
+	for _, a := range invalid {
+		err = cfg.AddAuth(map[string]*Auth{"invalid": a})
+		assert.Error(t, err, "%+v", a)
+	}
+}
+
+func TestAuthAllowsHost(t *testing.T) {
+	a := &Auth{AllowedHosts: []string{"api.mauve.de", "*.shop.mauve.de", "intranet.mauve.de:8443", "[::1]"}}
+
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{host: "api.mauve.de:443", allowed: true},
+		{host: "API.mauve.de.:80", allowed: true},
+		{host: "www.shop.mauve.de:443", allowed: true},
+		{host: "intranet.mauve.de:8443", allowed: true},
+		{host: "[::1]:443", allowed: true},
+		{host: "shop.mauve.de:443", allowed: false},
+		{host: "www.shop.mauve.de.evil.com:443", allowed: false},
+		{host: "evilshop.mauve.de:443", allowed: false},
+		{host: "intranet.mauve.de:443", allowed: false},
+		{host: "mauve.de:443", allowed: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			assert.Equal(t, test.allowed, a.AllowsHost(test.host))
+		})
+	}
+}
+
+func TestIsAuthFile(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	otherFile := filepath.Join(dir, "other.crt")
+	link := filepath.Join(dir, "link.crt")
+	for _, f := range []string{certFile, otherFile} {
+		if err := ioutil.WriteFile(f, []byte("cert"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(certFile, link); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{Auth: map[string]*Auth{"mtls": {CertFile: certFile, KeyFile: filepath.Join(dir, "tls.key")}}}
+
+	assert.True(t, cfg.IsAuthFile(certFile))
+	assert.True(t, cfg.IsAuthFile(link))
+	assert.True(t, cfg.IsAuthFile(filepath.Join(dir, ".", "tls.crt")))
+	assert.False(t, cfg.IsAuthFile(otherFile))
+	assert.False(t, cfg.IsAuthFile(filepath.Join(dir, "tls.key")))
+	assert.False(t, cfg.IsAuthFile(""))
+}
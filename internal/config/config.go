@@ -0,0 +1,141 @@
+// Package config contains the configuration file of the check server
+package config
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/definition"
+	"github.com/MauveSoftware/http-check/internal/tlsutil"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration of the check server
+type Config struct {
+	// ClientCertificates are certificates presented to checked targets, referenced by name in check requests
+	ClientCertificates map[string]*ClientCertificate `yaml:"client_certificates"`
+
+	// TrustStores are CA bundles used to verify certificates of checked targets, referenced by name in check requests
+	TrustStores map[string]*TrustStore `yaml:"trust_stores"`
+
+	// Auth defines authentications (OAuth2 clients, bearer tokens, API keys, basic auth and client certificates),
+	// referenced by name in check requests. Further auth can be loaded from a file, a secrets directory or the environment.
+	Auth map[string]*Auth `yaml:"auth"`
+
+	// Checks are run by the server on intervals, results are exposed as Prometheus metrics
+	Checks map[string]*ScheduledCheck `yaml:"checks"`
+
+	// Modules are check definitions without target used by the probe endpoint, referenced by name in probe requests
+	Modules map[string]*definition.Definition `yaml:"modules"`
+}
+
+// ClientCertificate is a certificate/key pair used to authenticate against a target
+type ClientCertificate struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+// TrustStore is a file containing PEM encoded CA certificates
+type TrustStore struct {
+	CAFile string `yaml:"ca_file"`
+}
+
+// ScheduledCheck is a check definition run on an interval
+type ScheduledCheck struct {
+	// Interval between two runs of the check (default: 1m)
+	Interval time.Duration `yaml:"interval"`
+
+	definition.Definition `yaml:",inline"`
+}
+
+// Load reads and validates the configuration file
+func Load(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read config file")
+	}
+
+	cfg := &Config{}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+
+	err = dec.Decode(cfg)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "Could not parse config file")
+	}
+
+	err = cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	for name, cert := range c.ClientCertificates {
+		if cert == nil || len(cert.CertFile) == 0 || len(cert.KeyFile) == 0 {
+			return fmt.Errorf("Client certificate %s: cert_file and key_file are required", name)
+		}
+
+		_, err := tls.LoadX509KeyPair(cert.CertFile, cert.KeyFile)
+		if err != nil {
+			return errors.Wrapf(err, "Client certificate %s", name)
+		}
+	}
+
+	for name, ts := range c.TrustStores {
+		if ts == nil || len(ts.CAFile) == 0 {
+			return fmt.Errorf("Trust store %s: ca_file is required", name)
+		}
+
+		_, err := tlsutil.LoadCertPool(ts.CAFile)
+		if err != nil {
+			return errors.Wrapf(err, "Trust store %s", name)
+		}
+	}
+
+	for name, a := range c.Auth {
+		if a == nil {
+			return fmt.Errorf("Auth %s: definition is empty", name)
+		}
+
+		err := a.validate()
+		if err != nil {
+			return errors.Wrapf(err, "Auth %s", name)
+		}
+	}
+
+	for name, chk := range c.Checks {
+		if chk == nil {
+			return fmt.Errorf("Check %s: definition is empty", name)
+		}
+
+		if chk.Interval < 0 {
+			return fmt.Errorf("Check %s: interval must not be negative", name)
+		}
+
+		_, err := chk.Request()
+		if err != nil {
+			return errors.Wrapf(err, "Check %s", name)
+		}
+	}
+
+	for name, m := range c.Modules {
+		if m == nil {
+			return fmt.Errorf("Module %s: definition is empty", name)
+		}
+
+		_, err := m.Request()
+		if err != nil {
+			return errors.Wrapf(err, "Module %s", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	err := ioutil.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadEmpty(t *testing.T) {
+	cfg, err := Load(writeConfig(t, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, cfg.ClientCertificates)
+}
+
+func TestLoadInvalidClientCertificate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "missing key file",
+			content: "client_certificates:\n  api:\n    cert_file: cert.pem\n",
+		},
+		{
+			name:    "files not found",
+			content: "client_certificates:\n  api:\n    cert_file: /nonexistent/cert.pem\n    key_file: /nonexistent/key.pem\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, test.content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadInvalidYAML(t *testing.T) {
+	_, err := Load(writeConfig(t, "client_certificates: ["))
+	assert.Error(t, err)
+}
+
+func TestLoadChecks(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+checks:
+  shop:
+    interval: 30s
+    host: shop.example.com
+    expect:
+      status: [200]
+  mail:
+    type: tls
+    host: mail.example.com:465
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, cfg.Checks, 2)
+	assert.Equal(t, 30*time.Second, cfg.Checks["shop"].Interval)
+	assert.Equal(t, "shop.example.com", cfg.Checks["shop"].Host)
+	assert.Equal(t, []uint32{200}, cfg.Checks["shop"].Expect.Status)
+	assert.Equal(t, time.Duration(0), cfg.Checks["mail"].Interval)
+}
+
+func TestLoadInvalidCheck(t *testing.T) {
+	_, err := Load(writeConfig(t, "checks:\n  api:\n    host: api.example.com\n    expect:\n      json: ['==UP']\n"))
+	assert.Error(t, err)
+}
+
+func TestLoadModules(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+modules:
+  http_2xx:
+    expect:
+      status: [200, 204]
+      body_regex: "ok"
+      cert_min_expire_days: 14
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []uint32{200, 204}, cfg.Modules["http_2xx"].Expect.Status)
+	assert.Equal(t, uint32(14), cfg.Modules["http_2xx"].Expect.CertMinExpireDays)
+}
+
+func TestLoadAuth(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+auth:
+  api:
+    type: oauth2
+    allowed_hosts: [api.example.com]
+    token_url: https://auth.example.com/token
+    client_id: monitoring
+    client_secret: secret
+    scopes: [read]
+  static:
+    type: bearer
+    allowed_hosts: [api.example.com]
+    token: t0k3n
+  shop:
+    type: api_key
+    allowed_hosts: [api.example.com]
+    header: X-Api-Key
+    key: k3y
+  appliance:
+    type: basic
+    allowed_hosts: [api.example.com]
+    username: monitoring
+    password: s3cr3t
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, cfg.Auth, 4)
+	assert.Equal(t, []string{"read"}, cfg.Auth["api"].Scopes)
+	assert.Equal(t, "X-Api-Key", cfg.Auth["shop"].Header)
+	assert.Equal(t, "monitoring", cfg.Auth["appliance"].Username)
+}
+
+func TestLoadInvalidAuth(t *testing.T) {
+	tests := []string{
+		"auth:\n  api:\n    type: oauth2\n    token_url: https://auth.example.com/token\n",
+		"auth:\n  api:\n    type: bearer\n",
+		"auth:\n  api:\n    type: bearer\n    token: t0k3n\n",
+		"auth:\n  api:\n    type: bearer\n    token: t0k3n\n    allowed_hosts: ['*']\n",
+		"auth:\n  api:\n    type: bearer\n    token: t0k3n\n    allowed_hosts: [api.*.de]\n",
+		"auth:\n  api:\n    type: api_key\n    key: k3y\n",
+		"auth:\n  api:\n    type: basic\n    password: s3cr3t\n",
+		"auth:\n  api:\n    type: digest\n",
+		"auth:\n  api:\n    key_file: key.pem\n",
+	}
+
+	for _, content := range tests {
+		_, err := Load(writeConfig(t, content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestLoadUnknownField(t *testing.T) {
+	_, err := Load(writeConfig(t, `
+checks:
+  shop:
+    host: shop.mauve.de
+    expect:
+      stauts: [200]
+`))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "stauts")
+	}
+}
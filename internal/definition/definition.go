@@ -0,0 +1,457 @@
+// Package definition contains declarative check definitions and their conversion to check requests
+package definition
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/pkg/errors"
+)
+
+// Definition describes a check. The fields correspond to the flags of the http-check client.
+type Definition struct {
+	// Type is the type of the check: http (default), tls or scenario (default if steps are defined)
+	Type string `yaml:"type"`
+
+	Protocol     string   `yaml:"protocol"`
+	Host         string   `yaml:"host"`
+	Path         string   `yaml:"path"`
+	Method       string   `yaml:"method"`
+	Headers      []string `yaml:"headers"`
+	Body         string   `yaml:"body"`
+	BodyFile     string   `yaml:"body_file"`
+	Username     string   `yaml:"username"`
+	Password     string   `yaml:"password"`
+	AuthScheme   string   `yaml:"auth_scheme"`
+	Auth         string   `yaml:"auth"`
+	CookieJar    bool     `yaml:"cookie_jar"`
+	Cookies      []string `yaml:"cookies"`
+	NoFollow     bool     `yaml:"no_follow"`
+	MaxRedirects *uint32  `yaml:"max_redirects"`
+	SNI          string   `yaml:"sni"`
+	StartTLS     string   `yaml:"starttls"`
+	Debug        bool     `yaml:"debug"`
+
+	TLS        TLS          `yaml:"tls"`
+	Expect     Expectations `yaml:"expect"`
+	Thresholds Thresholds   `yaml:"thresholds"`
+
+	// Steps are the requests of a scenario. Protocol, host, credentials and TLS settings are inherited from
+	// the scenario if not set, headers of the scenario are sent with each step.
+	Steps []*Step `yaml:"steps"`
+}
+
+// Step is a request of a scenario. Placeholders in format {{name}} in path, headers, cookies, body and credentials
+// are replaced with values extracted by previous steps.
+type Step struct {
+	Name    string       `yaml:"name"`
+	Extract []Extraction `yaml:"extract"`
+
+	Definition `yaml:",inline"`
+}
+
+// Extraction extracts a value from the response of a step. Exactly one source (header, regex or json) has to be defined.
+type Extraction struct {
+	Name   string `yaml:"name"`
+	Header string `yaml:"header"`
+	Regex  string `yaml:"regex"`
+	JSON   string `yaml:"json"`
+}
+
+// TLS defines the TLS settings used to connect to the target
+type TLS struct {
+	Insecure       bool   `yaml:"insecure"`
+	ClientCertName string `yaml:"client_cert_name"`
+	ClientCertFile string `yaml:"client_cert_file"`
+	ClientKeyFile  string `yaml:"client_key_file"`
+	TrustStore     string `yaml:"trust_store"`
+	CAFile         string `yaml:"ca_file"`
+	VerifiedChains bool   `yaml:"verified_chains"`
+}
+
+// Expectations defines the assertions of a check. Header, JSON and selector assertions use the expression
+// syntax of the corresponding client flags.
+type Expectations struct {
+	Status            []uint32 `yaml:"status"`
+	Headers           []string `yaml:"headers"`
+	Body              string   `yaml:"body"`
+	BodyRegex         string   `yaml:"body_regex"`
+	FinalURL          string   `yaml:"final_url"`
+	Location          string   `yaml:"location"`
+	Redirects         []string `yaml:"redirects"`
+	Cookies           []string `yaml:"cookies"`
+	JSON              []string `yaml:"json"`
+	CSS               []string `yaml:"css"`
+	XPath             []string `yaml:"xpath"`
+	XMLXPath          []string `yaml:"xml_xpath"`
+	Pins              []string `yaml:"pins"`
+	CertMinExpireDays uint32   `yaml:"cert_min_expire_days"`
+	TLSMinVersion     string   `yaml:"tls_min_version"`
+	TLSCiphers        []string `yaml:"tls_ciphers"`
+	HostnameInSAN     bool     `yaml:"hostname_in_san"`
+	SANs              []string `yaml:"sans"`
+	IssuerCN          string   `yaml:"issuer_cn"`
+	IssuerOrg         string   `yaml:"issuer_org"`
+	OCSPStapled       bool     `yaml:"ocsp_stapled"`
+}
+
+// Thresholds defines warning and critical thresholds in nagios range format
+type Thresholds struct {
+	ResponseTime       Threshold `yaml:"response_time"`
+	CertExpire         Threshold `yaml:"cert_expire"`
+	IntermediateExpire Threshold `yaml:"intermediate_expire"`
+}
+
+// Threshold is a pair of warning and critical ranges
+type Threshold struct {
+	Warning  string `yaml:"warning"`
+	Critical string `yaml:"critical"`
+}
+
+// Request converts the definition into a check request
+func (d *Definition) Request() (*api.Request, error) {
+	checkType, err := d.checkType()
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := parseHeaders(d.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	cookies, err := parseCookies(d.Cookies)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := d.requestBody()
+	if err != nil {
+		return nil, err
+	}
+
+	caPEM, err := d.caPEM()
+	if err != nil {
+		return nil, err
+	}
+
+	e := d.Expect
+
+	jsonAssertions := make([]*api.JSONPathAssertion, len(e.JSON))
+	for i, s := range e.JSON {
+		jsonAssertions[i], err = parseJSONAssertion(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	headerAssertions := make([]*api.HeaderAssertion, len(e.Headers))
+	for i, s := range e.Headers {
+		headerAssertions[i], err = parseHeaderAssertion(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	selectorAssertions, err := d.selectorAssertions()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &api.Request{
+		CheckType:                  checkType,
+		Protocol:                   d.Protocol,
+		Host:                       d.Host,
+		Path:                       d.Path,
+		Username:                   d.Username,
+		Password:                   d.Password,
+		AuthName:                   d.Auth,
+		AuthScheme:                 d.AuthScheme,
+		Method:                     d.requestMethod(body),
+		Headers:                    headers,
+		CookieJar:                  d.CookieJar,
+		Cookies:                    cookies,
+		Body:                       body,
+		ServerName:                 d.SNI,
+		Starttls:                   d.StartTLS,
+		Debug:                      d.Debug,
+		Insecure:                   d.TLS.Insecure,
+		ClientCertName:             d.TLS.ClientCertName,
+		ClientCertFile:             d.TLS.ClientCertFile,
+		ClientKeyFile:              d.TLS.ClientKeyFile,
+		TrustStoreName:             d.TLS.TrustStore,
+		CaPem:                      caPEM,
+		IncludeVerifiedChains:      d.TLS.VerifiedChains,
+		ExpectedStatusCode:         e.Status,
+		ExpectedHeaders:            headerAssertions,
+		ExpectedBody:               e.Body,
+		ExpectedBodyRegex:          e.BodyRegex,
+		ExpectedFinalUrl:           e.FinalURL,
+		ExpectedLocation:           e.Location,
+		ExpectedRedirectChain:      e.Redirects,
+		ExpectedCookies:            e.Cookies,
+		ExpectedJson:               jsonAssertions,
+		ExpectedSelectors:          selectorAssertions,
+		ExpectedPins:               e.Pins,
+		CertExpireDays:             e.CertMinExpireDays,
+		MinTlsVersion:              e.TLSMinVersion,
+		AllowedCipherSuites:        e.TLSCiphers,
+		ExpectHostnameInSan:        e.HostnameInSAN,
+		ExpectedSans:               e.SANs,
+		ExpectedIssuerCn:           e.IssuerCN,
+		ExpectedIssuerOrg:          e.IssuerOrg,
+		ExpectOcspStapled:          e.OCSPStapled,
+		ResponseTimeWarning:        d.Thresholds.ResponseTime.Warning,
+		ResponseTimeCritical:       d.Thresholds.ResponseTime.Critical,
+		CertExpireWarning:          d.Thresholds.CertExpire.Warning,
+		CertExpireCritical:         d.Thresholds.CertExpire.Critical,
+		IntermediateExpireWarning:  d.Thresholds.IntermediateExpire.Warning,
+		IntermediateExpireCritical: d.Thresholds.IntermediateExpire.Critical,
+	}
+
+	if len(req.Protocol) == 0 {
+		req.Protocol = "https"
+	}
+
+	if d.NoFollow || (d.MaxRedirects != nil && *d.MaxRedirects == 0) {
+		// max_redirects 0 can not be distinguished from the server default in the request, so redirects are not followed
+		req.RedirectMode = api.RedirectMode_NO_FOLLOW
+	} else if d.MaxRedirects != nil {
+		req.MaxRedirects = *d.MaxRedirects
+	}
+
+	if checkType == api.CheckType_SCENARIO {
+		req.Steps, err = d.steps()
+		if err != nil {
+			return nil, err
+		}
+	} else if len(d.Steps) > 0 {
+		return nil, fmt.Errorf("Steps are only supported by scenario checks")
+	}
+
+	return req, nil
+}
+
+func (d *Definition) steps() ([]*api.Step, error) {
+	if len(d.Steps) == 0 {
+		return nil, fmt.Errorf("Scenario has no steps")
+	}
+
+	steps := make([]*api.Step, len(d.Steps))
+	for i, s := range d.Steps {
+		if s == nil {
+			return nil, fmt.Errorf("Step #%d is empty", i+1)
+		}
+
+		name := s.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+
+		if len(s.Steps) > 0 {
+			return nil, fmt.Errorf("Step '%s': steps can not be nested", name)
+		}
+
+		req, err := d.stepDefinition(s, i == 0).Request()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Step '%s'", name)
+		}
+
+		extract := make([]*api.Extraction, len(s.Extract))
+		for j, e := range s.Extract {
+			extract[j], err = e.toAPI()
+			if err != nil {
+				return nil, errors.Wrapf(err, "Step '%s'", name)
+			}
+		}
+
+		steps[i] = &api.Step{
+			Name:    s.Name,
+			Request: req,
+			Extract: extract,
+		}
+	}
+
+	return steps, nil
+}
+
+// stepDefinition returns the definition of the step with the settings inherited from the scenario.
+// Cookies of the scenario are only sent by the first step, the following steps use the cookies stored in the jar.
+func (d *Definition) stepDefinition(s *Step, first bool) *Definition {
+	sd := s.Definition
+	sd.Headers = append(append([]string{}, d.Headers...), s.Headers...)
+	sd.Debug = d.Debug
+
+	if first {
+		sd.Cookies = append(append([]string{}, d.Cookies...), s.Cookies...)
+	}
+
+	if len(sd.Protocol) == 0 {
+		sd.Protocol = d.Protocol
+	}
+
+	if len(sd.Host) == 0 {
+		sd.Host = d.Host
+	}
+
+	if len(sd.Username) == 0 && len(sd.Auth) == 0 {
+		sd.Username, sd.Password, sd.AuthScheme, sd.Auth = d.Username, d.Password, d.AuthScheme, d.Auth
+	}
+
+	if sd.TLS == (TLS{}) {
+		sd.TLS = d.TLS
+	}
+
+	return &sd
+}
+
+func (e Extraction) toAPI() (*api.Extraction, error) {
+	res := &api.Extraction{Name: e.Name}
+	sources := 0
+
+	if len(e.Header) > 0 {
+		res.Source, res.Expression = api.Extraction_HEADER, e.Header
+		sources++
+	}
+
+	if len(e.Regex) > 0 {
+		res.Source, res.Expression = api.Extraction_REGEX, e.Regex
+		sources++
+	}
+
+	if len(e.JSON) > 0 {
+		res.Source, res.Expression = api.Extraction_JSON_PATH, e.JSON
+		sources++
+	}
+
+	if len(e.Name) == 0 || sources != 1 {
+		return nil, fmt.Errorf("Invalid extraction '%s' (name and exactly one of header, regex or json are required)", e.Name)
+	}
+
+	return res, nil
+}
+
+func (d *Definition) checkType() (api.CheckType, error) {
+	if len(d.Type) == 0 && len(d.Steps) > 0 {
+		return api.CheckType_SCENARIO, nil
+	}
+
+	switch strings.ToLower(d.Type) {
+	case "", "http":
+		return api.CheckType_HTTP, nil
+	case "tls":
+		return api.CheckType_TLS, nil
+	case "scenario":
+		return api.CheckType_SCENARIO, nil
+	default:
+		return 0, fmt.Errorf("Unsupported check type '%s' (expected http, tls or scenario)", d.Type)
+	}
+}
+
+func (d *Definition) selectorAssertions() ([]*api.SelectorAssertion, error) {
+	expressions := []struct {
+		values       []string
+		selectorType api.SelectorAssertion_SelectorType
+	}{
+		{values: d.Expect.CSS, selectorType: api.SelectorAssertion_CSS},
+		{values: d.Expect.XPath, selectorType: api.SelectorAssertion_XPATH_HTML},
+		{values: d.Expect.XMLXPath, selectorType: api.SelectorAssertion_XPATH_XML},
+	}
+
+	res := []*api.SelectorAssertion{}
+	for _, e := range expressions {
+		for _, v := range e.values {
+			a, err := parseSelectorAssertion(v, e.selectorType)
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, a)
+		}
+	}
+
+	return res, nil
+}
+
+func parseHeaders(values []string) ([]*api.Header, error) {
+	res := make([]*api.Header, len(values))
+	for i, v := range values {
+		parts := strings.SplitN(v, ":", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 {
+			return nil, fmt.Errorf("Invalid header '%s' (expected format: 'Name: value')", v)
+		}
+
+		res[i] = &api.Header{
+			Name:  strings.TrimSpace(parts[0]),
+			Value: strings.TrimSpace(parts[1]),
+		}
+	}
+
+	return res, nil
+}
+
+func parseCookies(values []string) ([]*api.Cookie, error) {
+	res := make([]*api.Cookie, len(values))
+	for i, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 {
+			return nil, fmt.Errorf("Invalid cookie '%s' (expected format: 'name=value')", v)
+		}
+
+		res[i] = &api.Cookie{
+			Name:  strings.TrimSpace(parts[0]),
+			Value: strings.TrimSpace(parts[1]),
+		}
+	}
+
+	return res, nil
+}
+
+func (d *Definition) requestBody() ([]byte, error) {
+	if len(d.Body) > 0 && len(d.BodyFile) > 0 {
+		return nil, fmt.Errorf("Body and body file can not be used together")
+	}
+
+	if len(d.BodyFile) > 0 {
+		b, err := ioutil.ReadFile(d.BodyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "Could not read data file")
+		}
+
+		return b, nil
+	}
+
+	if len(d.Body) > 0 {
+		return []byte(d.Body), nil
+	}
+
+	return nil, nil
+}
+
+func (d *Definition) requestMethod(body []byte) string {
+	if len(d.Method) > 0 {
+		return strings.ToUpper(d.Method)
+	}
+
+	if body != nil {
+		return http.MethodPost
+	}
+
+	return http.MethodGet
+}
+
+func (d *Definition) caPEM() ([]byte, error) {
+	if len(d.TLS.CAFile) == 0 {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadFile(d.TLS.CAFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read CA file")
+	}
+
+	return b, nil
+}
@@ -0,0 +1,240 @@
+package definition
+
+import (
+	"testing"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest(t *testing.T) {
+	d := &Definition{
+		Host:     "api.example.com",
+		Path:     "/orders",
+		Headers:  []string{"Content-Type: application/json"},
+		Body:     `{"id": 1}`,
+		NoFollow: true,
+		TLS: TLS{
+			TrustStore: "internal",
+		},
+		Expect: Expectations{
+			Status:  []uint32{201},
+			Headers: []string{"Location"},
+			JSON:    []string{"$.id==1"},
+			CSS:     []string{"title"},
+		},
+		Thresholds: Thresholds{
+			ResponseTime: Threshold{Warning: "1", Critical: "2"},
+		},
+	}
+
+	req, err := d.Request()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, api.CheckType_HTTP, req.CheckType)
+	assert.Equal(t, "https", req.Protocol)
+	assert.Equal(t, "api.example.com", req.Host)
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, []byte(`{"id": 1}`), req.Body)
+	assert.Equal(t, []*api.Header{{Name: "Content-Type", Value: "application/json"}}, req.Headers)
+	assert.Equal(t, api.RedirectMode_NO_FOLLOW, req.RedirectMode)
+	assert.Equal(t, "internal", req.TrustStoreName)
+	assert.Equal(t, []uint32{201}, req.ExpectedStatusCode)
+	assert.Len(t, req.ExpectedHeaders, 1)
+	assert.Equal(t, []*api.JSONPathAssertion{{Path: "$.id", Type: api.JSONPathAssertion_EQUALS, Value: "1"}}, req.ExpectedJson)
+	assert.Len(t, req.ExpectedSelectors, 1)
+	assert.Equal(t, "1", req.ResponseTimeWarning)
+	assert.Equal(t, "2", req.ResponseTimeCritical)
+}
+
+func TestRequestMaxRedirects(t *testing.T) {
+	max := func(n uint32) *uint32 {
+		return &n
+	}
+
+	req, err := (&Definition{Host: "example.com"}).Request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.RedirectMode_FOLLOW, req.RedirectMode)
+		assert.Equal(t, uint32(0), req.MaxRedirects)
+	}
+
+	req, err = (&Definition{Host: "example.com", MaxRedirects: max(3)}).Request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.RedirectMode_FOLLOW, req.RedirectMode)
+		assert.Equal(t, uint32(3), req.MaxRedirects)
+	}
+
+	req, err = (&Definition{Host: "example.com", MaxRedirects: max(0)}).Request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.RedirectMode_NO_FOLLOW, req.RedirectMode)
+	}
+}
+
+func TestRequestDefaultMethod(t *testing.T) {
+	req, err := (&Definition{Host: "example.com", Method: "head"}).Request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "HEAD", req.Method)
+	}
+
+	req, err = (&Definition{Host: "example.com"}).Request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "GET", req.Method)
+	}
+}
+
+func TestRequestTLS(t *testing.T) {
+	req, err := (&Definition{Type: "tls", Host: "mail.example.com:25", StartTLS: "smtp"}).Request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.CheckType_TLS, req.CheckType)
+		assert.Equal(t, "smtp", req.Starttls)
+	}
+}
+
+func TestRequestInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		def  *Definition
+	}{
+		{name: "type", def: &Definition{Type: "ftp"}},
+		{name: "header", def: &Definition{Headers: []string{"invalid"}}},
+		{name: "body", def: &Definition{Body: "a", BodyFile: "b"}},
+		{name: "json", def: &Definition{Expect: Expectations{JSON: []string{"==UP"}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.def.Request()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRequestScenario(t *testing.T) {
+	d := &Definition{
+		Host:       "shop.example.com",
+		Headers:    []string{"User-Agent: monitoring"},
+		Username:   "admin",
+		Password:   "secret",
+		AuthScheme: "digest",
+		Debug:      true,
+		TLS:        TLS{TrustStore: "internal"},
+		Steps: []*Step{
+			{
+				Name: "login",
+				Extract: []Extraction{
+					{Name: "csrf", Regex: `name="csrf" value="([^"]+)"`},
+					{Name: "session", Header: "X-Session"},
+				},
+				Definition: Definition{
+					Path:   "/login",
+					Expect: Expectations{Status: []uint32{200}},
+				},
+			},
+			{
+				Definition: Definition{
+					Auth:    "api",
+					Host:    "api.example.com",
+					Path:    "/orders",
+					Headers: []string{"X-CSRF-Token: {{csrf}}"},
+					TLS:     TLS{Insecure: true},
+				},
+				Extract: []Extraction{{Name: "id", JSON: "$.orders[0].id"}},
+			},
+		},
+	}
+
+	req, err := d.Request()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, api.CheckType_SCENARIO, req.CheckType)
+	if !assert.Len(t, req.Steps, 2) {
+		return
+	}
+
+	login := req.Steps[0]
+	assert.Equal(t, "login", login.Name)
+	assert.Equal(t, "shop.example.com", login.Request.Host)
+	assert.Equal(t, "https", login.Request.Protocol)
+	assert.Equal(t, "/login", login.Request.Path)
+	assert.Equal(t, "admin", login.Request.Username)
+	assert.Equal(t, "digest", login.Request.AuthScheme)
+	assert.Empty(t, login.Request.AuthName)
+	assert.Equal(t, "internal", login.Request.TrustStoreName)
+	assert.True(t, login.Request.Debug)
+	assert.Equal(t, []uint32{200}, login.Request.ExpectedStatusCode)
+	assert.Equal(t, []*api.Extraction{
+		{Name: "csrf", Source: api.Extraction_REGEX, Expression: `name="csrf" value="([^"]+)"`},
+		{Name: "session", Source: api.Extraction_HEADER, Expression: "X-Session"},
+	}, login.Extract)
+
+	orders := req.Steps[1]
+	assert.Equal(t, "api.example.com", orders.Request.Host)
+	assert.Equal(t, []*api.Header{
+		{Name: "User-Agent", Value: "monitoring"},
+		{Name: "X-CSRF-Token", Value: "{{csrf}}"},
+	}, orders.Request.Headers)
+	assert.True(t, orders.Request.Insecure)
+	assert.Equal(t, "api", orders.Request.AuthName)
+	assert.Empty(t, orders.Request.Username)
+	assert.Empty(t, orders.Request.TrustStoreName)
+	assert.Equal(t, api.Extraction_JSON_PATH, orders.Extract[0].Source)
+}
+
+func TestRequestScenarioInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		def  *Definition
+	}{
+		{name: "no steps", def: &Definition{Type: "scenario"}},
+		{name: "steps in tls check", def: &Definition{Type: "tls", Steps: []*Step{{}}}},
+		{name: "nested steps", def: &Definition{Steps: []*Step{{Definition: Definition{Steps: []*Step{{}}}}}}},
+		{name: "extraction without source", def: &Definition{Steps: []*Step{{Extract: []Extraction{{Name: "a"}}}}}},
+		{name: "extraction with two sources", def: &Definition{Steps: []*Step{{Extract: []Extraction{{Name: "a", Header: "X", JSON: "$.a"}}}}}},
+		{name: "invalid step", def: &Definition{Steps: []*Step{{Definition: Definition{Headers: []string{"invalid"}}}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.def.Request()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRequestCookies(t *testing.T) {
+	d := &Definition{
+		Host:      "example.com",
+		CookieJar: true,
+		Cookies:   []string{"consent=1", "lang = de"},
+		Expect:    Expectations{Cookies: []string{"session;Secure"}},
+	}
+
+	req, err := d.Request()
+	if assert.NoError(t, err) {
+		assert.True(t, req.CookieJar)
+		assert.Equal(t, []*api.Cookie{{Name: "consent", Value: "1"}, {Name: "lang", Value: "de"}}, req.Cookies)
+		assert.Equal(t, []string{"session;Secure"}, req.ExpectedCookies)
+	}
+
+	_, err = (&Definition{Host: "example.com", Cookies: []string{"consent"}}).Request()
+	assert.Error(t, err)
+
+	d = &Definition{
+		Host:    "example.com",
+		Cookies: []string{"consent=1"},
+		Steps: []*Step{
+			{Definition: Definition{Path: "/"}},
+			{Definition: Definition{Path: "/cart", Cookies: []string{"cart={{id}}"}}},
+		},
+	}
+
+	req, err = d.Request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []*api.Cookie{{Name: "consent", Value: "1"}}, req.Steps[0].Request.Cookies)
+		assert.Equal(t, []*api.Cookie{{Name: "cart", Value: "{{id}}"}}, req.Steps[1].Request.Cookies)
+	}
+}
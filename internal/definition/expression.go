@@ -0,0 +1,240 @@
+package definition
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+)
+
+var (
+	numericOperators  = []string{"==", "!=", "<=", ">=", "<", ">"}
+	selectorOperators = []string{"==", "~="}
+	headerOperators   = []string{"==", "~=", "*="}
+)
+
+// parseHeaderAssertion parses a header assertion expression in one of the following formats:
+// `<name>` (present), `!<name>` (absent), `<name>==<value>` (equals), `<name>~=<regex>` (matches)
+// or `<name>*=<value>` (contains)
+func parseHeaderAssertion(s string) (*api.HeaderAssertion, error) {
+	if strings.HasPrefix(s, "!") {
+		name := strings.TrimSpace(s[1:])
+		if len(name) == 0 {
+			return nil, fmt.Errorf("Invalid header assertion '%s': name is missing", s)
+		}
+
+		return &api.HeaderAssertion{
+			Name: name,
+			Mode: api.HeaderAssertion_ABSENT,
+		}, nil
+	}
+
+	name, op, value := splitOperator(s, headerOperators)
+	if len(name) == 0 {
+		return nil, fmt.Errorf("Invalid header assertion '%s': name is missing", s)
+	}
+
+	a := &api.HeaderAssertion{
+		Name:  name,
+		Value: value,
+	}
+
+	switch op {
+	case "":
+		a.Mode = api.HeaderAssertion_PRESENT
+	case "==":
+		a.Mode = api.HeaderAssertion_EQUALS
+	case "~=":
+		a.Mode = api.HeaderAssertion_MATCHES
+	case "*=":
+		a.Mode = api.HeaderAssertion_CONTAINS
+	}
+
+	return a, nil
+}
+
+// parseJSONAssertion parses a JSON assertion expression in one of the following formats:
+// `<path>` (exists), `<path>==<value>` (equals), `<path><op><number>` (numeric comparison)
+// or `length(<path>)<op><number>` (length of an array)
+func parseJSONAssertion(s string) (*api.JSONPathAssertion, error) {
+	if path, rest, ok := parseFunction(s, "length"); ok {
+		_, op, value := splitOperator(rest, numericOperators)
+		if len(op) == 0 {
+			return nil, fmt.Errorf("Invalid JSON assertion '%s': length requires an operator", s)
+		}
+
+		return &api.JSONPathAssertion{
+			Path:     path,
+			Type:     api.JSONPathAssertion_LENGTH,
+			Operator: op,
+			Value:    value,
+		}, nil
+	}
+
+	path, op, value := splitOperator(s, numericOperators)
+	if len(path) == 0 {
+		return nil, fmt.Errorf("Invalid JSON assertion '%s': path is missing", s)
+	}
+
+	switch op {
+	case "":
+		return &api.JSONPathAssertion{
+			Path: path,
+			Type: api.JSONPathAssertion_EXISTS,
+		}, nil
+	case "==":
+		return &api.JSONPathAssertion{
+			Path:  path,
+			Type:  api.JSONPathAssertion_EQUALS,
+			Value: value,
+		}, nil
+	default:
+		return &api.JSONPathAssertion{
+			Path:     path,
+			Type:     api.JSONPathAssertion_COMPARE,
+			Operator: op,
+			Value:    value,
+		}, nil
+	}
+}
+
+// parseSelectorAssertion parses a CSS/XPath assertion expression in one of the following formats:
+// `<selector>` (exists), `<selector>==<text>` (text equals), `<selector>~=<regex>` (text matches),
+// `attr(<selector>,<name>)==<value>` (attribute equals) or `count(<selector>)<op><number>` (number of matches)
+func parseSelectorAssertion(s string, selectorType api.SelectorAssertion_SelectorType) (*api.SelectorAssertion, error) {
+	a := &api.SelectorAssertion{
+		SelectorType: selectorType,
+	}
+
+	if args, rest, ok := parseFunction(s, "count"); ok {
+		_, op, value := splitOperator(rest, numericOperators)
+		if len(op) == 0 {
+			return nil, fmt.Errorf("Invalid assertion '%s': count requires an operator", s)
+		}
+
+		a.Selector = args
+		a.Type = api.SelectorAssertion_COUNT
+		a.Operator = op
+		a.Value = value
+		return a, nil
+	}
+
+	if args, rest, ok := parseFunction(s, "attr"); ok {
+		i := strings.LastIndex(args, ",")
+		_, op, value := splitOperator(rest, selectorOperators)
+		if i < 0 || op != "==" {
+			return nil, fmt.Errorf("Invalid assertion '%s' (expected format: attr(<selector>,<name>)==<value>)", s)
+		}
+
+		a.Selector = strings.TrimSpace(args[:i])
+		a.Type = api.SelectorAssertion_ATTRIBUTE_EQUALS
+		a.Attribute = strings.TrimSpace(args[i+1:])
+		a.Value = value
+		return a, nil
+	}
+
+	selector, op, value := splitOperator(s, selectorOperators)
+	a.Selector = selector
+	a.Value = value
+
+	switch op {
+	case "":
+		a.Type = api.SelectorAssertion_EXISTS
+	case "==":
+		a.Type = api.SelectorAssertion_TEXT_EQUALS
+	case "~=":
+		a.Type = api.SelectorAssertion_TEXT_MATCHES
+	}
+
+	if len(a.Selector) == 0 {
+		return nil, fmt.Errorf("Invalid assertion '%s': selector is missing", s)
+	}
+
+	return a, nil
+}
+
+// parseFunction parses expressions in format `<name>(<args>)<rest>`
+func parseFunction(s, name string) (args, rest string, ok bool) {
+	if !strings.HasPrefix(s, name+"(") {
+		return "", "", false
+	}
+
+	end := -1
+	scanTopLevel(s[len(name)+1:], func(i int, depth int) bool {
+		if depth == -1 {
+			end = i
+			return false
+		}
+
+		return true
+	})
+
+	if end < 0 {
+		return "", "", false
+	}
+
+	start := len(name) + 1
+	return strings.TrimSpace(s[start : start+end]), s[start+end+1:], true
+}
+
+// splitOperator splits the expression at the first operator not enclosed in quotes, brackets or parentheses
+func splitOperator(s string, operators []string) (left, op, right string) {
+	left = strings.TrimSpace(s)
+
+	scanTopLevel(s, func(i int, depth int) bool {
+		if depth != 0 {
+			return true
+		}
+
+		for _, o := range operators {
+			if strings.HasPrefix(s[i:], o) {
+				left, op, right = strings.TrimSpace(s[:i]), o, strings.TrimSpace(s[i+len(o):])
+				return false
+			}
+		}
+
+		return true
+	})
+
+	return left, op, right
+}
+
+// scanTopLevel calls fn for each character outside of quotes with the current nesting depth of brackets and parentheses.
+// Closing a bracket not opened in s results in a depth of -1. Scanning stops when fn returns false.
+func scanTopLevel(s string, fn func(i int, depth int) bool) {
+	depth := 0
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+
+			continue
+		}
+
+		switch c {
+		case '\'', '"':
+			quote = c
+			continue
+		case '[', '(':
+			depth++
+			continue
+		case ']', ')':
+			depth--
+			if depth < 0 {
+				fn(i, depth)
+				return
+			}
+
+			continue
+		}
+
+		if !fn(i, depth) {
+			return
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package definition
+
+import (
+	"testing"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSONAssertion(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected *api.JSONPathAssertion
+	}{
+		{
+			expr:     "$.db.ok",
+			expected: &api.JSONPathAssertion{Path: "$.db.ok", Type: api.JSONPathAssertion_EXISTS},
+		},
+		{
+			expr:     "$.status==UP",
+			expected: &api.JSONPathAssertion{Path: "$.status", Type: api.JSONPathAssertion_EQUALS, Value: "UP"},
+		},
+		{
+			expr:     "$.latency <= 100",
+			expected: &api.JSONPathAssertion{Path: "$.latency", Type: api.JSONPathAssertion_COMPARE, Operator: "<=", Value: "100"},
+		},
+		{
+			expr:     "$.items[?(@.price>10)].name==foo",
+			expected: &api.JSONPathAssertion{Path: "$.items[?(@.price>10)].name", Type: api.JSONPathAssertion_EQUALS, Value: "foo"},
+		},
+		{
+			expr:     "length($.nodes)>=2",
+			expected: &api.JSONPathAssertion{Path: "$.nodes", Type: api.JSONPathAssertion_LENGTH, Operator: ">=", Value: "2"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			a, err := parseJSONAssertion(test.expr)
+			if assert.Nil(t, err) {
+				assert.Equal(t, test.expected, a)
+			}
+		})
+	}
+}
+
+func TestParseSelectorAssertion(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected *api.SelectorAssertion
+	}{
+		{
+			expr:     "ul > li.product",
+			expected: &api.SelectorAssertion{Selector: "ul > li.product", Type: api.SelectorAssertion_EXISTS},
+		},
+		{
+			expr:     "title==Mauve Shop",
+			expected: &api.SelectorAssertion{Selector: "title", Type: api.SelectorAssertion_TEXT_EQUALS, Value: "Mauve Shop"},
+		},
+		{
+			expr:     "a[title='a==b']~=^Mauve",
+			expected: &api.SelectorAssertion{Selector: "a[title='a==b']", Type: api.SelectorAssertion_TEXT_MATCHES, Value: "^Mauve"},
+		},
+		{
+			expr:     "attr(a.logo, href)==/",
+			expected: &api.SelectorAssertion{Selector: "a.logo", Type: api.SelectorAssertion_ATTRIBUTE_EQUALS, Attribute: "href", Value: "/"},
+		},
+		{
+			expr:     "count(li:not(.sold-out))>=3",
+			expected: &api.SelectorAssertion{Selector: "li:not(.sold-out)", Type: api.SelectorAssertion_COUNT, Operator: ">=", Value: "3"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			a, err := parseSelectorAssertion(test.expr, api.SelectorAssertion_CSS)
+			if assert.Nil(t, err) {
+				assert.Equal(t, test.expected, a)
+			}
+		})
+	}
+}
+
+func TestParseHeaderAssertion(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected *api.HeaderAssertion
+	}{
+		{
+			expr:     "Strict-Transport-Security",
+			expected: &api.HeaderAssertion{Name: "Strict-Transport-Security", Mode: api.HeaderAssertion_PRESENT},
+		},
+		{
+			expr:     "!Server",
+			expected: &api.HeaderAssertion{Name: "Server", Mode: api.HeaderAssertion_ABSENT},
+		},
+		{
+			expr:     "Content-Type==application/json",
+			expected: &api.HeaderAssertion{Name: "Content-Type", Mode: api.HeaderAssertion_EQUALS, Value: "application/json"},
+		},
+		{
+			expr:     "Cache-Control~=max-age=\\d+",
+			expected: &api.HeaderAssertion{Name: "Cache-Control", Mode: api.HeaderAssertion_MATCHES, Value: "max-age=\\d+"},
+		},
+		{
+			expr:     "Content-Security-Policy*=default-src 'self'",
+			expected: &api.HeaderAssertion{Name: "Content-Security-Policy", Mode: api.HeaderAssertion_CONTAINS, Value: "default-src 'self'"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			a, err := parseHeaderAssertion(test.expr)
+			if assert.Nil(t, err) {
+				assert.Equal(t, test.expected, a)
+			}
+		})
+	}
+}
+
+func TestParseInvalidAssertions(t *testing.T) {
+	_, err := parseJSONAssertion("length($.nodes)")
+	assert.NotNil(t, err)
+
+	_, err = parseSelectorAssertion("count(li)", api.SelectorAssertion_CSS)
+	assert.NotNil(t, err)
+
+	_, err = parseSelectorAssertion("attr(a)==b", api.SelectorAssertion_CSS)
+	assert.NotNil(t, err)
+
+	_, err = parseHeaderAssertion("!")
+	assert.NotNil(t, err)
+}
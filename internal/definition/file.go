@@ -0,0 +1,150 @@
+package definition
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// variableRegex matches variables in format ${NAME} or ${NAME:-default}
+var variableRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// File is a file containing named check definitions
+type File struct {
+	Checks map[string]*Definition `yaml:"checks"`
+}
+
+// LoadFile reads a YAML (or JSON) file containing check definitions.
+// Variables in format ${NAME} (or ${NAME:-default}) are replaced with the value of the environment variable.
+func LoadFile(path string) (*File, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read check definitions")
+	}
+
+	return Parse(b, os.LookupEnv)
+}
+
+// Parse parses check definitions. lookup is used to resolve variables in values.
+func Parse(b []byte, lookup func(string) (string, bool)) (*File, error) {
+	var root yaml.Node
+	err := yaml.Unmarshal(b, &root)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not parse check definitions")
+	}
+
+	err = expandNode(&root, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{}
+	if len(root.Content) == 0 {
+		return f, nil
+	}
+
+	err = decodeStrict(&root, f)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not parse check definitions")
+	}
+
+	return f, nil
+}
+
+// decodeStrict decodes the node and rejects unknown fields
+func decodeStrict(n *yaml.Node, v interface{}) error {
+	b, err := yaml.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+
+	return dec.Decode(v)
+}
+
+// expandNode replaces variables in all scalar values (keys are not expanded)
+func expandNode(n *yaml.Node, lookup func(string) (string, bool)) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		v, err := expandVariables(n.Value, lookup)
+		if err != nil {
+			return err
+		}
+
+		if v != n.Value && n.Style == 0 {
+			// resolve the type of unquoted values again (e.g. numbers)
+			n.Tag = ""
+		}
+		n.Value = v
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			err := expandNode(n.Content[i], lookup)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		for _, c := range n.Content {
+			err := expandNode(c, lookup)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Get returns the check definition with the specified name
+func (f *File) Get(name string) (*Definition, error) {
+	d, found := f.Checks[name]
+	if !found || d == nil {
+		return nil, fmt.Errorf("Check '%s' not defined (available: %s)", name, strings.Join(f.Names(), ", "))
+	}
+
+	return d, nil
+}
+
+// Names returns the sorted names of all defined checks
+func (f *File) Names() []string {
+	names := make([]string, 0, len(f.Checks))
+	for name := range f.Checks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func expandVariables(s string, lookup func(string) (string, bool)) (string, error) {
+	missing := []string{}
+
+	res := variableRegex.ReplaceAllStringFunc(s, func(m string) string {
+		sub := variableRegex.FindStringSubmatch(m)
+		if v, found := lookup(sub[1]); found {
+			return v
+		}
+
+		if len(sub[2]) > 0 {
+			return sub[3]
+		}
+
+		missing = append(missing, sub[1])
+		return m
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("Undefined variables: %s", strings.Join(missing, ", "))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,169 @@
+package definition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lookupFrom(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, found := env[name]
+		return v, found
+	}
+}
+
+func TestParse(t *testing.T) {
+	b := []byte(`
+checks:
+  shop-home:
+    host: ${SHOP_HOST}
+    path: /
+    headers:
+      - "Authorization: Bearer ${TOKEN}"
+    max_redirects: ${MAX_REDIRECTS:-3}
+    expect:
+      status: [200]
+      json:
+        - "$.status==UP"
+      cert_min_expire_days: ${CERT_DAYS}
+    thresholds:
+      response_time:
+        warning: "${WARN:-1}"
+        critical: "2"
+  mail:
+    type: tls
+    host: mail.example.com:25
+    starttls: smtp
+`)
+
+	env := map[string]string{
+		"SHOP_HOST": "shop.example.com",
+		"TOKEN":     "secret",
+		"CERT_DAYS": "14",
+	}
+
+	f, err := Parse(b, lookupFrom(env))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"mail", "shop-home"}, f.Names())
+
+	d, err := f.Get("shop-home")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "shop.example.com", d.Host)
+	assert.Equal(t, []string{"Authorization: Bearer secret"}, d.Headers)
+	if assert.NotNil(t, d.MaxRedirects) {
+		assert.Equal(t, uint32(3), *d.MaxRedirects)
+	}
+	assert.Equal(t, []uint32{200}, d.Expect.Status)
+	assert.Equal(t, []string{"$.status==UP"}, d.Expect.JSON)
+	assert.Equal(t, uint32(14), d.Expect.CertMinExpireDays)
+	assert.Equal(t, "1", d.Thresholds.ResponseTime.Warning)
+	assert.Equal(t, "2", d.Thresholds.ResponseTime.Critical)
+
+	d, err = f.Get("mail")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "tls", d.Type)
+	assert.Equal(t, "smtp", d.StartTLS)
+}
+
+func TestParseJSON(t *testing.T) {
+	b := []byte(`{"checks": {"api": {"host": "${HOST}", "expect": {"status": [200, 204]}}}}`)
+
+	f, err := Parse(b, lookupFrom(map[string]string{"HOST": "api.example.com"}))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	d, err := f.Get("api")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "api.example.com", d.Host)
+	assert.Equal(t, []uint32{200, 204}, d.Expect.Status)
+}
+
+func TestParseUndefinedVariable(t *testing.T) {
+	b := []byte(`
+checks:
+  api:
+    host: ${HOST}
+    password: ${PASSWORD}
+`)
+
+	_, err := Parse(b, lookupFrom(map[string]string{"HOST": "api.example.com"}))
+	assert.EqualError(t, err, "Undefined variables: PASSWORD")
+}
+
+func TestParseUnknownField(t *testing.T) {
+	b := []byte(`
+checks:
+  api:
+    host: api.example.com
+    expect:
+      stauts: [200]
+`)
+
+	_, err := Parse(b, lookupFrom(nil))
+	assert.Error(t, err)
+}
+
+func TestGetUndefinedCheck(t *testing.T) {
+	f := &File{
+		Checks: map[string]*Definition{
+			"b": {},
+			"a": {},
+		},
+	}
+
+	_, err := f.Get("c")
+	assert.EqualError(t, err, "Check 'c' not defined (available: a, b)")
+}
+
+func TestParseScenario(t *testing.T) {
+	b := []byte(`
+checks:
+  checkout:
+    host: ${SHOP_HOST}
+    steps:
+      - name: login
+        path: /login
+        method: POST
+        body: "user=monitoring&password=${SHOP_PASSWORD}"
+        extract:
+          - name: token
+            json: $.token
+      - name: cart
+        path: /cart
+        headers:
+          - "Authorization: Bearer {{token}}"
+        expect:
+          status: [200]
+`)
+
+	env := map[string]string{"SHOP_HOST": "shop.example.com", "SHOP_PASSWORD": "secret"}
+	f, err := Parse(b, lookupFrom(env))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	d, err := f.Get("checkout")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if assert.Len(t, d.Steps, 2) {
+		assert.Equal(t, "user=monitoring&password=secret", d.Steps[0].Body)
+		assert.Equal(t, []Extraction{{Name: "token", JSON: "$.token"}}, d.Steps[0].Extract)
+		assert.Equal(t, []string{"Authorization: Bearer {{token}}"}, d.Steps[1].Headers)
+	}
+}
@@ -0,0 +1,87 @@
+package probe
+
+import (
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// recordProbe registers the metrics of a single probe at reg. Names follow blackbox_exporter where applicable.
+// Without response (check could not be performed) only success, status and duration are recorded.
+func recordProbe(reg prometheus.Registerer, checkType api.CheckType, resp *api.Response, d time.Duration) {
+	success := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_success",
+		Help: "Displays whether or not the probe was a success",
+	})
+	duration := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_duration_seconds",
+		Help: "Returns how long the probe took to complete in seconds",
+	})
+	checkStatus := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_check_status",
+		Help: "Status of the check (0 = OK, 1 = WARNING, 2 = CRITICAL, 3 = UNKNOWN)",
+	})
+	reg.MustRegister(success, duration, checkStatus)
+
+	duration.Set(d.Seconds())
+
+	if resp == nil {
+		checkStatus.Set(float64(api.Status_UNKNOWN))
+		return
+	}
+
+	if resp.Success {
+		success.Set(1)
+	}
+	checkStatus.Set(float64(resp.Status))
+
+	p := resp.Performance
+	if p == nil {
+		return
+	}
+
+	phases := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "probe_phase_duration_seconds",
+		Help: "Duration of the phases of the probe (dns, connect, tls, ttfb)",
+	}, []string{"phase"})
+	reg.MustRegister(phases)
+
+	phases.WithLabelValues("dns").Set(p.DnsLookupSeconds)
+	phases.WithLabelValues("connect").Set(p.ConnectSeconds)
+	phases.WithLabelValues("tls").Set(p.TlsHandshakeSeconds)
+
+	if p.HasCertificate {
+		// the check server reports the first expiring certificate of the chain
+		earliestCertExpiry := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "probe_ssl_earliest_cert_expiry",
+			Help: "Returns earliest SSL cert expiry in unixtime",
+		})
+		reg.MustRegister(earliestCertExpiry)
+		earliestCertExpiry.Set(float64(p.CertNotAfterSeconds))
+	}
+
+	if checkType != api.CheckType_HTTP {
+		return
+	}
+
+	phases.WithLabelValues("ttfb").Set(p.FirstByteSeconds)
+
+	statusCode := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_http_status_code",
+		Help: "Response HTTP status code",
+	})
+	contentLength := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_http_content_length",
+		Help: "Length of http content response",
+	})
+	bodyLength := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "probe_http_uncompressed_body_length",
+		Help: "Length of uncompressed response body",
+	})
+	reg.MustRegister(statusCode, contentLength, bodyLength)
+
+	statusCode.Set(float64(resp.StatusCode))
+	contentLength.Set(float64(resp.ContentLength))
+	bodyLength.Set(float64(p.BodySizeBytes))
+}
@@ -0,0 +1,127 @@
+// Package probe provides an HTTP endpoint for the multi-target exporter pattern of Prometheus (compatible to blackbox_exporter)
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/definition"
+	"github.com/MauveSoftware/http-check/internal/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultTimeout is used if Prometheus does not send a scrape timeout
+const DefaultTimeout = 10 * time.Second
+
+// timeoutOffset is subtracted from the scrape timeout to have enough time left to return the metrics
+const timeoutOffset = 500 * time.Millisecond
+
+// Handler handles probe requests (/probe?target=...&module=...)
+type Handler struct {
+	checker server.Checker
+	modules map[string]*definition.Definition
+}
+
+// NewHandler creates a probe handler for the modules
+func NewHandler(checker server.Checker, modules map[string]*definition.Definition) *Handler {
+	return &Handler{
+		checker: checker,
+		modules: modules,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if len(target) == 0 {
+		http.Error(w, "Target parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	moduleName := r.URL.Query().Get("module")
+	if len(moduleName) == 0 {
+		http.Error(w, "Module parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	module, found := h.modules[moduleName]
+	if !found {
+		http.Error(w, fmt.Sprintf("Unknown module %q", moduleName), http.StatusBadRequest)
+		return
+	}
+
+	req, err := requestForTarget(module, target)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), scrapeTimeout(r))
+	defer cancel()
+
+	start := time.Now()
+	resp, err := h.checker.Check(ctx, req)
+	if status.Code(err) == codes.InvalidArgument {
+		http.Error(w, status.Convert(err).Message(), http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		logrus.Errorf("Probe of %s (module %s) failed: %v", target, moduleName, err)
+	}
+
+	reg := prometheus.NewRegistry()
+	recordProbe(reg, req.CheckType, resp, time.Since(start))
+
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
+// requestForTarget creates the check request for the module and target. Targets of HTTP modules
+// can be URLs (protocol, host and path are taken from the URL) or hosts.
+func requestForTarget(module *definition.Definition, target string) (*api.Request, error) {
+	d := *module
+	d.Host = target
+
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid target %q: %v", target, err)
+		}
+
+		if len(u.Host) == 0 {
+			return nil, fmt.Errorf("Invalid target %q: host is missing", target)
+		}
+
+		d.Protocol = u.Scheme
+		d.Host = u.Host
+
+		if len(u.Path) > 0 || len(u.RawQuery) > 0 {
+			d.Path = u.RequestURI()
+		}
+	}
+
+	return d.Request()
+}
+
+func scrapeTimeout(r *http.Request) time.Duration {
+	v, err := strconv.ParseFloat(r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"), 64)
+	if err != nil || v <= 0 {
+		return DefaultTimeout
+	}
+
+	timeout := time.Duration(v * float64(time.Second))
+	if timeout > timeoutOffset {
+		timeout -= timeoutOffset
+	}
+
+	return timeout
+}
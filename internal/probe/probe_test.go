@@ -0,0 +1,158 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/definition"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeChecker struct {
+	req  *api.Request
+	resp *api.Response
+	err  error
+}
+
+func (c *fakeChecker) Check(ctx context.Context, req *api.Request) (*api.Response, error) {
+	c.req = req
+	return c.resp, c.err
+}
+
+var modules = map[string]*definition.Definition{
+	"http_2xx": {
+		Path: "/health",
+		Expect: definition.Expectations{
+			Status:            []uint32{200},
+			CertMinExpireDays: 14,
+		},
+	},
+	"tls": {
+		Type: "tls",
+	},
+}
+
+func probe(h http.Handler, target, module string) *httptest.ResponseRecorder {
+	q := url.Values{}
+	q.Set("target", target)
+	q.Set("module", module)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/probe?"+q.Encode(), nil))
+
+	return rec
+}
+
+func TestProbe(t *testing.T) {
+	checker := &fakeChecker{
+		resp: &api.Response{
+			Success:       true,
+			Status:        api.Status_OK,
+			StatusCode:    200,
+			ContentLength: 40,
+			Performance: &api.Performance{
+				TotalSeconds:        0.1,
+				BodySizeBytes:       42,
+				HasCertificate:      true,
+				CertNotAfterSeconds: 1893456000,
+			},
+		},
+	}
+	h := NewHandler(checker, modules)
+
+	rec := probe(h, "https://www.example.com/status?full=1", "http_2xx")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, "https", checker.req.Protocol)
+	assert.Equal(t, "www.example.com", checker.req.Host)
+	assert.Equal(t, "/status?full=1", checker.req.Path)
+	assert.Equal(t, []uint32{200}, checker.req.ExpectedStatusCode)
+	assert.Equal(t, uint32(14), checker.req.CertExpireDays)
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "probe_success 1\n")
+	assert.Contains(t, body, "probe_check_status 0\n")
+	assert.Contains(t, body, "probe_http_status_code 200\n")
+	assert.Contains(t, body, "probe_http_content_length 40\n")
+	assert.Contains(t, body, "probe_http_uncompressed_body_length 42\n")
+	assert.Contains(t, body, "probe_ssl_earliest_cert_expiry 1.893456e+09\n")
+	assert.Contains(t, body, `probe_phase_duration_seconds{phase="ttfb"}`)
+}
+
+func TestProbeHostTarget(t *testing.T) {
+	checker := &fakeChecker{resp: &api.Response{Status: api.Status_CRITICAL}}
+	h := NewHandler(checker, modules)
+
+	rec := probe(h, "www.example.com", "http_2xx")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https", checker.req.Protocol)
+	assert.Equal(t, "www.example.com", checker.req.Host)
+	assert.Equal(t, "/health", checker.req.Path)
+	assert.Contains(t, rec.Body.String(), "probe_success 0\n")
+
+	rec = probe(h, "mail.example.com:465", "tls")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, api.CheckType_TLS, checker.req.CheckType)
+	assert.NotContains(t, rec.Body.String(), "probe_http_status_code")
+}
+
+func TestProbeCheckError(t *testing.T) {
+	checker := &fakeChecker{err: status.Error(codes.DeadlineExceeded, "No worker available")}
+	h := NewHandler(checker, modules)
+
+	rec := probe(h, "www.example.com", "http_2xx")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "probe_success 0\n")
+	assert.Contains(t, rec.Body.String(), "probe_check_status 3\n")
+}
+
+func TestProbeInvalidRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		module string
+		err    error
+	}{
+		{name: "missing target", module: "http_2xx"},
+		{name: "missing module", target: "www.example.com"},
+		{name: "unknown module", target: "www.example.com", module: "icmp"},
+		{name: "invalid target", target: "https:///path", module: "http_2xx"},
+		{name: "rejected by server", target: "www.example.com", module: "http_2xx", err: status.Error(codes.InvalidArgument, "invalid")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := NewHandler(&fakeChecker{err: test.err}, modules)
+			rec := probe(h, test.target, test.module)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestScrapeTimeout(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{header: "", expected: DefaultTimeout},
+		{header: "invalid", expected: DefaultTimeout},
+		{header: "5", expected: 4500 * time.Millisecond},
+		{header: "0.2", expected: 200 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("header %q", test.header), func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/probe", nil)
+			r.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", test.header)
+			assert.Equal(t, test.expected, scrapeTimeout(r))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "http_check"
+
+type metrics struct {
+	success       *prometheus.GaugeVec
+	status        *prometheus.GaugeVec
+	statusCode    *prometheus.GaugeVec
+	certNotAfter  *prometheus.GaugeVec
+	bodySize      *prometheus.GaugeVec
+	lastRun       *prometheus.GaugeVec
+	errors        *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	phaseDuration *prometheus.HistogramVec
+}
+
+func newMetrics() *metrics {
+	labels := []string{"check"}
+
+	return &metrics{
+		success: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "success",
+			Help:      "Result of the last run of the check (1 = OK, 0 = not OK)",
+		}, labels),
+		status: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "status",
+			Help:      "Status of the last run of the check (0 = OK, 1 = WARNING, 2 = CRITICAL, 3 = UNKNOWN)",
+		}, labels),
+		statusCode: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "status_code",
+			Help:      "HTTP status code of the last response",
+		}, labels),
+		certNotAfter: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cert_expiry_timestamp_seconds",
+			Help:      "Expiration date of the first expiring certificate of the chain returned by the target as unix timestamp",
+		}, labels),
+		bodySize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "body_size_bytes",
+			Help:      "Size of the last response body",
+		}, labels),
+		lastRun: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_run_timestamp_seconds",
+			Help:      "Time of the last run of the check as unix timestamp",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Number of runs which could not be performed (e.g. no worker available)",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "duration_seconds",
+			Help:      "Total duration of the check",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		phaseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "phase_duration_seconds",
+			Help:      "Duration of the phases of the check (dns, connect, tls, ttfb)",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"check", "phase"}),
+	}
+}
+
+func (m *metrics) register(reg prometheus.Registerer) error {
+	collectors := []prometheus.Collector{
+		m.success,
+		m.status,
+		m.statusCode,
+		m.certNotAfter,
+		m.bodySize,
+		m.lastRun,
+		m.errors,
+		m.duration,
+		m.phaseDuration,
+	}
+
+	for _, c := range collectors {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// record updates the metrics of the check. Status code, body size and time to first byte are only recorded for HTTP checks.
+func (m *metrics) record(name string, checkType api.CheckType, resp *api.Response) {
+	m.lastRun.WithLabelValues(name).Set(float64(time.Now().Unix()))
+	m.success.WithLabelValues(name).Set(boolToFloat(resp.Success))
+	m.status.WithLabelValues(name).Set(float64(resp.Status))
+
+	isHTTP := checkType == api.CheckType_HTTP
+	if isHTTP {
+		m.statusCode.WithLabelValues(name).Set(float64(resp.StatusCode))
+	}
+
+	p := resp.Performance
+	if p == nil {
+		return
+	}
+
+	m.duration.WithLabelValues(name).Observe(p.TotalSeconds)
+	m.phaseDuration.WithLabelValues(name, "dns").Observe(p.DnsLookupSeconds)
+	m.phaseDuration.WithLabelValues(name, "connect").Observe(p.ConnectSeconds)
+	m.phaseDuration.WithLabelValues(name, "tls").Observe(p.TlsHandshakeSeconds)
+
+	if isHTTP {
+		m.phaseDuration.WithLabelValues(name, "ttfb").Observe(p.FirstByteSeconds)
+		m.bodySize.WithLabelValues(name).Set(float64(p.BodySizeBytes))
+	}
+
+	if p.HasCertificate {
+		m.certNotAfter.WithLabelValues(name).Set(float64(p.CertNotAfterSeconds))
+	} else {
+		m.certNotAfter.DeleteLabelValues(name)
+	}
+}
+
+func (m *metrics) recordError(name string) {
+	m.lastRun.WithLabelValues(name).Set(float64(time.Now().Unix()))
+	m.errors.WithLabelValues(name).Inc()
+	m.success.WithLabelValues(name).Set(0)
+	m.status.WithLabelValues(name).Set(float64(api.Status_UNKNOWN))
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,109 @@
+// Package scheduler runs configured checks on intervals and exposes the results as Prometheus metrics
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/config"
+	"github.com/MauveSoftware/http-check/internal/server"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultInterval is used for checks without interval
+const DefaultInterval = time.Minute
+
+type job struct {
+	name     string
+	interval time.Duration
+	req      *api.Request
+}
+
+// Scheduler runs checks on intervals
+type Scheduler struct {
+	checker server.Checker
+	jobs    []*job
+	metrics *metrics
+}
+
+// New creates a scheduler for the checks. Metrics are registered at reg.
+func New(checker server.Checker, checks map[string]*config.ScheduledCheck, reg prometheus.Registerer) (*Scheduler, error) {
+	s := &Scheduler{
+		checker: checker,
+		metrics: newMetrics(),
+	}
+
+	for name, c := range checks {
+		req, err := c.Request()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Check %s", name)
+		}
+
+		interval := c.Interval
+		if interval == 0 {
+			interval = DefaultInterval
+		}
+
+		s.jobs = append(s.jobs, &job{
+			name:     name,
+			interval: interval,
+			req:      req,
+		})
+	}
+
+	sort.Slice(s.jobs, func(i, j int) bool {
+		return s.jobs[i].name < s.jobs[j].name
+	})
+
+	err := s.metrics.register(reg)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Start runs all checks until ctx is canceled
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, j := range s.jobs {
+		logrus.Infof("Scheduling check %s every %v", j.name, j.interval)
+		go s.schedule(ctx, j)
+	}
+}
+
+func (s *Scheduler) schedule(ctx context.Context, j *job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx, j)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	ctx, cancel := context.WithTimeout(ctx, j.interval)
+	defer cancel()
+
+	resp, err := s.checker.Check(ctx, j.req)
+	if err != nil {
+		logrus.Errorf("Check %s failed: %v", j.name, err)
+		s.metrics.recordError(j.name)
+		return
+	}
+
+	if !resp.Success {
+		logrus.Warnf("Check %s: %s - %s", j.name, resp.Status, resp.Message)
+	}
+
+	s.metrics.record(j.name, j.req.CheckType, resp)
+}
@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/config"
+	"github.com/MauveSoftware/http-check/internal/definition"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeChecker struct {
+	responses map[string]*api.Response
+}
+
+func (c *fakeChecker) Check(ctx context.Context, req *api.Request) (*api.Response, error) {
+	resp, found := c.responses[req.Host]
+	if !found {
+		return nil, fmt.Errorf("no worker available")
+	}
+
+	return resp, nil
+}
+
+func TestSchedulerRecordsMetrics(t *testing.T) {
+	certNotAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	checker := &fakeChecker{
+		responses: map[string]*api.Response{
+			"ok.example.com": {
+				Success:    true,
+				Status:     api.Status_OK,
+				StatusCode: 200,
+				Performance: &api.Performance{
+					TotalSeconds:        0.2,
+					BodySizeBytes:       1024,
+					HasCertificate:      true,
+					CertNotAfterSeconds: certNotAfter.Unix(),
+				},
+			},
+			"mail.example.com:465": {
+				Success:     true,
+				Status:      api.Status_OK,
+				Performance: &api.Performance{TotalSeconds: 0.1},
+			},
+			"down.example.com": {
+				Status:      api.Status_CRITICAL,
+				StatusCode:  503,
+				Performance: &api.Performance{TotalSeconds: 0.1},
+			},
+		},
+	}
+
+	checks := map[string]*config.ScheduledCheck{
+		"ok":    {Definition: definition.Definition{Host: "ok.example.com"}},
+		"down":  {Definition: definition.Definition{Host: "down.example.com"}, Interval: time.Hour},
+		"error": {Definition: definition.Definition{Host: "error.example.com"}},
+		"mail":  {Definition: definition.Definition{Type: "tls", Host: "mail.example.com:465"}},
+	}
+
+	reg := prometheus.NewRegistry()
+	s, err := New(checker, checks, reg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, s.jobs, 4)
+	assert.Equal(t, "down", s.jobs[0].name)
+	assert.Equal(t, time.Hour, s.jobs[0].interval)
+	assert.Equal(t, DefaultInterval, s.jobs[1].interval)
+
+	for _, j := range s.jobs {
+		s.run(context.Background(), j)
+	}
+
+	m := s.metrics
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.success.WithLabelValues("ok")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.success.WithLabelValues("down")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.success.WithLabelValues("error")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.status.WithLabelValues("down")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(m.status.WithLabelValues("error")))
+	assert.Equal(t, float64(503), testutil.ToFloat64(m.statusCode.WithLabelValues("down")))
+	assert.Equal(t, float64(certNotAfter.Unix()), testutil.ToFloat64(m.certNotAfter.WithLabelValues("ok")))
+	assert.Equal(t, float64(1024), testutil.ToFloat64(m.bodySize.WithLabelValues("ok")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.errors.WithLabelValues("error")))
+	assert.Equal(t, 3, testutil.CollectAndCount(m.duration))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.statusCode))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.certNotAfter))
+}
+
+func TestNewInvalidCheck(t *testing.T) {
+	checks := map[string]*config.ScheduledCheck{
+		"invalid": {Definition: definition.Definition{Type: "ftp"}},
+	}
+
+	_, err := New(&fakeChecker{}, checks, prometheus.NewRegistry())
+	assert.Error(t, err)
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/pkg/check"
+	"github.com/pkg/errors"
+)
+
+func addJSONPathAssertion(c *check.Check, a *api.JSONPathAssertion) error {
+	if len(a.Path) == 0 {
+		return fmt.Errorf("Path must not be empty")
+	}
+
+	switch a.Type {
+	case api.JSONPathAssertion_EQUALS:
+		c.AssertJSONPath(a.Path, a.Value)
+	case api.JSONPathAssertion_EXISTS:
+		c.AssertJSONPathExists(a.Path)
+	case api.JSONPathAssertion_COMPARE:
+		op, err := check.ParseOperator(a.Operator)
+		if err != nil {
+			return err
+		}
+
+		v, err := strconv.ParseFloat(a.Value, 64)
+		if err != nil {
+			return fmt.Errorf("Value '%s' is not a number", a.Value)
+		}
+
+		c.AssertJSONPathCompare(a.Path, op, v)
+	case api.JSONPathAssertion_LENGTH:
+		op, err := check.ParseOperator(a.Operator)
+		if err != nil {
+			return err
+		}
+
+		v, err := strconv.Atoi(a.Value)
+		if err != nil {
+			return fmt.Errorf("Length '%s' is not an integer", a.Value)
+		}
+
+		c.AssertJSONPathLength(a.Path, op, v)
+	default:
+		return fmt.Errorf("Unsupported assertion type %v", a.Type)
+	}
+
+	return nil
+}
+
+func addSelectorAssertion(c *check.Check, a *api.SelectorAssertion) error {
+	var sel *check.Selector
+	var err error
+
+	switch a.SelectorType {
+	case api.SelectorAssertion_CSS:
+		sel, err = check.NewCSSSelector(a.Selector)
+	case api.SelectorAssertion_XPATH_HTML:
+		sel, err = check.NewXPathSelector(a.Selector, false)
+	case api.SelectorAssertion_XPATH_XML:
+		sel, err = check.NewXPathSelector(a.Selector, true)
+	default:
+		err = fmt.Errorf("Unsupported selector type %v", a.SelectorType)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	switch a.Type {
+	case api.SelectorAssertion_EXISTS:
+		c.AssertSelectorExists(sel)
+	case api.SelectorAssertion_TEXT_EQUALS:
+		c.AssertSelectorText(sel, a.Value)
+	case api.SelectorAssertion_TEXT_MATCHES:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("Invalid regex '%s'", a.Value)
+		}
+
+		c.AssertSelectorTextMatches(sel, a.Value)
+	case api.SelectorAssertion_ATTRIBUTE_EQUALS:
+		if len(a.Attribute) == 0 {
+			return fmt.Errorf("Attribute name must not be empty")
+		}
+
+		c.AssertSelectorAttribute(sel, a.Attribute, a.Value)
+	case api.SelectorAssertion_COUNT:
+		op, err := check.ParseOperator(a.Operator)
+		if err != nil {
+			return err
+		}
+
+		v, err := strconv.Atoi(a.Value)
+		if err != nil {
+			return fmt.Errorf("Count '%s' is not an integer", a.Value)
+		}
+
+		c.AssertSelectorCount(sel, op, v)
+	default:
+		return fmt.Errorf("Unsupported assertion type %v", a.Type)
+	}
+
+	return nil
+}
+
+func addHeaderAssertion(c *check.Check, a *api.HeaderAssertion) error {
+	if len(a.Name) == 0 {
+		return fmt.Errorf("Header name must not be empty")
+	}
+
+	switch a.Mode {
+	case api.HeaderAssertion_EQUALS:
+		c.AssertHeaderExists(a.Name, a.Value)
+	case api.HeaderAssertion_MATCHES:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("Invalid regex '%s'", a.Value)
+		}
+
+		c.AssertHeaderMatches(a.Name, a.Value)
+	case api.HeaderAssertion_CONTAINS:
+		c.AssertHeaderContains(a.Name, a.Value)
+	case api.HeaderAssertion_PRESENT:
+		c.AssertHeaderPresent(a.Name)
+	case api.HeaderAssertion_ABSENT:
+		c.AssertHeaderAbsent(a.Name)
+	default:
+		return fmt.Errorf("Unsupported assertion mode %v", a.Mode)
+	}
+
+	return nil
+}
+
+func addTLSAssertions(c *check.Check, req *api.Request) error {
+	if len(req.MinTlsVersion) > 0 {
+		v, err := check.ParseTLSVersion(req.MinTlsVersion)
+		if err != nil {
+			return err
+		}
+
+		c.AssertTLSMinVersion(v)
+	}
+
+	if len(req.AllowedCipherSuites) > 0 {
+		suites := make([]uint16, len(req.AllowedCipherSuites))
+		for i, name := range req.AllowedCipherSuites {
+			var err error
+			suites[i], err = check.ParseCipherSuite(name)
+			if err != nil {
+				return err
+			}
+		}
+
+		c.AssertCipherSuiteIn(suites)
+	}
+
+	if req.ExpectHostnameInSan {
+		c.AssertCertificateHostname("")
+	}
+
+	for _, san := range req.ExpectedSans {
+		c.AssertCertificateHostname(san)
+	}
+
+	warning, critical, err := parseThresholds(req.IntermediateExpireWarning, req.IntermediateExpireCritical)
+	if err != nil {
+		return errors.Wrap(err, "Invalid intermediate certificate expiration threshold")
+	}
+
+	if warning != nil || critical != nil {
+		c.AssertIntermediateExpireThresholds(warning, critical)
+	}
+
+	if len(req.ExpectedIssuerCn) > 0 {
+		c.AssertIssuerCommonName(req.ExpectedIssuerCn)
+	}
+
+	if len(req.ExpectedIssuerOrg) > 0 {
+		c.AssertIssuerOrganization(req.ExpectedIssuerOrg)
+	}
+
+	if req.ExpectOcspStapled {
+		c.AssertOCSPStapled()
+	}
+
+	return nil
+}
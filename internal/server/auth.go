@@ -0,0 +1,95 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/config"
+	"github.com/MauveSoftware/http-check/pkg/check"
+	"github.com/pkg/errors"
+)
+
+// newAuthenticators creates the authenticators defined in the configuration.
+// They are created once, so OAuth2 tokens are cached across checks. Basic auth depends on the auth scheme
+// of the request and is created per check.
+func (s *HTTPCheckServer) newAuthenticators() map[string]check.Authenticator {
+	res := make(map[string]check.Authenticator, len(s.cfg.Auth))
+	for name, a := range s.cfg.Auth {
+		switch a.Type {
+		case config.AuthOAuth2:
+			res[name] = check.NewOAuth2ClientCredentials(s.tokenClient(), a.TokenURL, a.ClientID, a.ClientSecret, a.Scopes)
+		case config.AuthBearer:
+			res[name] = check.BearerToken(a.Token)
+		case config.AuthAPIKey:
+			res[name] = check.APIKey(a.Header, a.Key)
+		}
+	}
+
+	return res
+}
+
+// tokenClient returns the client used to request tokens from OAuth2 token endpoints
+func (s *HTTPCheckServer) tokenClient() *http.Client {
+	cl := s.newHttpClient(nil)
+	cl.Timeout = s.reqTimeout
+
+	return cl
+}
+
+// authForRequest returns the auth referenced by the request (nil if none).
+// The auth is only returned if the host the request connects to is one of its allowed hosts.
+func (w *worker) authForRequest(req *api.Request) (*config.Auth, error) {
+	if len(req.AuthName) == 0 {
+		return nil, nil
+	}
+
+	a, found := w.cfg.Auth[req.AuthName]
+	if !found {
+		return nil, fmt.Errorf("Unknown auth '%s'", req.AuthName)
+	}
+
+	host, err := targetHost(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.AllowsHost(host) {
+		return nil, fmt.Errorf("Auth '%s' is not allowed for host '%s'", req.AuthName, host)
+	}
+
+	return a, nil
+}
+
+// targetHost returns the host and port the request connects to
+func targetHost(req *api.Request) (string, error) {
+	if req.CheckType == api.CheckType_TLS {
+		return req.Host, nil
+	}
+
+	u, err := url.Parse(requestURL(req))
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid URL")
+	}
+
+	port := u.Port()
+	if len(port) == 0 {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// authenticatorForRequest returns the authenticator of the auth referenced by the request (nil if none)
+func (w *worker) authenticatorForRequest(req *api.Request, a *config.Auth) (check.Authenticator, error) {
+	if a.Type == config.AuthBasic {
+		return check.PasswordAuth(req.AuthScheme, a.Username, a.Password)
+	}
+
+	return w.auth[req.AuthName], nil
+}
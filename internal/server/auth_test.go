@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/config"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuth(t *testing.T) {
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		fmt.Fprint(w, `{"access_token": "oauth-token", "expires_in": 300}`)
+	}))
+	defer tokenServer.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer oauth-token" && r.Header.Get("X-Api-Key") != "k3y" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	hosts := []string{"127.0.0.1"}
+	cfg := &config.Config{
+		Auth: map[string]*config.Auth{
+			"api": {Type: config.AuthOAuth2, AllowedHosts: hosts, TokenURL: tokenServer.URL, ClientID: "id", ClientSecret: "secret"},
+			"key": {Type: config.AuthAPIKey, AllowedHosts: hosts, Header: "X-Api-Key", Key: "k3y"},
+		},
+	}
+	s := New(1, time.Second, time.Second, time.Second, WithConfig(cfg))
+
+	req := func(auth string) *api.Request {
+		return &api.Request{
+			Protocol:           "http",
+			Host:               strings.TrimPrefix(ts.URL, "http://"),
+			AuthName:           auth,
+			ExpectedStatusCode: []uint32{200},
+		}
+	}
+
+	for _, name := range []string{"api", "api", "key"} {
+		resp, err := s.Check(context.Background(), req(name))
+		if assert.NoError(t, err) {
+			assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+		}
+	}
+	assert.Equal(t, 1, tokenRequests)
+
+	_, err := s.Check(context.Background(), req("unknown"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	r := req("key")
+	r.Username = "admin"
+	_, err = s.Check(context.Background(), r)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	r = req("key")
+	r.Host = strings.Replace(r.Host, "127.0.0.1", "localhost", 1)
+	_, err = s.Check(context.Background(), r)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "host not allowed")
+
+	r = req("api")
+	r.Path = "@evil.example.com/"
+	_, err = s.Check(context.Background(), r)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "host in path")
+}
+
+func TestAuthBasic(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, _ := r.BasicAuth()
+		if r.Header.Get("Authorization") != "Bearer t0k3n" && (u != "monitoring" || p != "s3cr3t") {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	hosts := []string{"127.0.0.1"}
+	cfg := &config.Config{
+		Auth: map[string]*config.Auth{
+			"basic": {Type: config.AuthBasic, AllowedHosts: hosts, Username: "monitoring", Password: "s3cr3t"},
+			"token": {Type: config.AuthBearer, AllowedHosts: hosts, Token: "t0k3n"},
+		},
+	}
+	s := New(1, time.Second, time.Second, time.Second, WithConfig(cfg))
+
+	req := func(auth string) *api.Request {
+		return &api.Request{
+			Protocol:           "http",
+			Host:               strings.TrimPrefix(ts.URL, "http://"),
+			AuthName:           auth,
+			ExpectedStatusCode: []uint32{200},
+		}
+	}
+
+	for _, name := range []string{"basic", "token"} {
+		resp, err := s.Check(context.Background(), req(name))
+		if assert.NoError(t, err) {
+			assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+		}
+	}
+
+	_, err := s.Check(context.Background(), req("unknown"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	r := req("basic")
+	r.Username = "admin"
+	_, err = s.Check(context.Background(), r)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	r = req("token")
+	r.CheckType = api.CheckType_TLS
+	_, err = s.Check(context.Background(), r)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAuthScheme(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), `Digest username="monitoring"`) {
+			w.Header().Set("WWW-Authenticate", `Digest realm="test", qop="auth", nonce="abc"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer ts.Close()
+
+	hosts := []string{"127.0.0.1"}
+	cfg := &config.Config{
+		Auth: map[string]*config.Auth{
+			"digest": {Type: config.AuthBasic, AllowedHosts: hosts, Username: "monitoring", Password: "s3cr3t"},
+			"token":  {Type: config.AuthBearer, AllowedHosts: hosts, Token: "t0k3n"},
+		},
+	}
+	s := New(1, time.Second, time.Second, time.Second, WithConfig(cfg))
+
+	req := func() *api.Request {
+		return &api.Request{
+			Protocol:           "http",
+			Host:               strings.TrimPrefix(ts.URL, "http://"),
+			AuthScheme:         "digest",
+			ExpectedStatusCode: []uint32{200},
+		}
+	}
+
+	r := req()
+	r.Username, r.Password = "monitoring", "s3cr3t"
+	resp, err := s.Check(context.Background(), r)
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+	}
+
+	r = req()
+	r.AuthName = "digest"
+	resp, err = s.Check(context.Background(), r)
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+	}
+
+	r = req()
+	r.AuthName = "token"
+	_, err = s.Check(context.Background(), r)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "without username")
+
+	r = req()
+	r.Username, r.AuthScheme = "monitoring", "kerberos"
+	_, err = s.Check(context.Background(), r)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "unsupported scheme")
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/pkg/check"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (w *worker) processScenario(ctx context.Context, req *api.Request) (*api.Response, error) {
+	out := &strings.Builder{}
+	s, err := w.scenarioForRequest(req, out)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res := s.RunContext(ctx)
+
+	resp := &api.Response{
+		Success:      res.Status == check.OK,
+		Status:       statusToAPI(res.Status),
+		Message:      res.Message,
+		DebugMessage: out.String(),
+		Performance:  performanceFromMetrics(s.Metrics()),
+		Assertions:   assertionsToAPI(res.Assertions),
+		Steps:        stepsToAPI(s.Steps()),
+	}
+
+	steps := s.Steps()
+	if last := steps[len(steps)-1]; last.Response != nil {
+		resp.FinalUrl = last.Response.URL
+		resp.StatusCode = uint32(last.Response.StatusCode)
+	}
+
+	return resp, nil
+}
+
+func (w *worker) scenarioForRequest(req *api.Request, out io.Writer) (*check.Scenario, error) {
+	if len(req.Steps) == 0 {
+		return nil, fmt.Errorf("Scenario has no steps")
+	}
+
+	if hasHTTPAssertions(req) || len(req.ResponseTimeWarning) > 0 || len(req.ResponseTimeCritical) > 0 {
+		return nil, fmt.Errorf("Assertions and thresholds of a scenario have to be defined on its steps")
+	}
+
+	steps := make([]*check.Step, len(req.Steps))
+	for i, st := range req.Steps {
+		name := st.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+
+		step, err := w.scenarioStep(name, st, req.Debug, out)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Step '%s'", name)
+		}
+
+		steps[i] = step
+	}
+
+	return check.NewScenario(steps...), nil
+}
+
+func (w *worker) scenarioStep(name string, st *api.Step, debug bool, out io.Writer) (*check.Step, error) {
+	if st.Request == nil {
+		return nil, fmt.Errorf("Request is missing")
+	}
+
+	if st.Request.CheckType != api.CheckType_HTTP || len(st.Request.Steps) > 0 {
+		return nil, fmt.Errorf("Only HTTP checks are supported as steps")
+	}
+
+	// placeholders are only substituted in request fields, so all assertions and settings can be validated up front
+	_, err := w.checkForRequest(st.Request, ioutil.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	extractors := make([]*check.Extractor, len(st.Extract))
+	for i, e := range st.Extract {
+		extractors[i], err = extractorForAPI(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &check.Step{
+		Name:    name,
+		Extract: extractors,
+		NewCheck: func(vars map[string]string) (*check.Check, error) {
+			req, err := substituteRequest(st.Request, vars)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Debug = debug
+			return w.checkForRequest(req, out)
+		},
+	}, nil
+}
+
+func extractorForAPI(e *api.Extraction) (*check.Extractor, error) {
+	if len(e.Name) == 0 || len(e.Expression) == 0 {
+		return nil, fmt.Errorf("Name and expression of extractions are required")
+	}
+
+	switch e.Source {
+	case api.Extraction_HEADER:
+		return check.ExtractHeader(e.Name, e.Expression), nil
+	case api.Extraction_REGEX:
+		ex, err := check.ExtractRegex(e.Name, e.Expression)
+		return ex, errors.Wrapf(err, "Invalid regex for %s", e.Name)
+	case api.Extraction_JSON_PATH:
+		return check.ExtractJSONPath(e.Name, e.Expression), nil
+	default:
+		return nil, fmt.Errorf("Unsupported extraction source %v", e.Source)
+	}
+}
+
+// substituteRequest returns a copy of the request with placeholders in path, headers, cookies, body and credentials replaced
+func substituteRequest(req *api.Request, vars map[string]string) (*api.Request, error) {
+	r := proto.Clone(req).(*api.Request)
+
+	var err error
+	fields := []*string{&r.Path, &r.Username, &r.Password}
+	for _, h := range r.Headers {
+		fields = append(fields, &h.Value)
+	}
+
+	for _, ck := range r.Cookies {
+		fields = append(fields, &ck.Value)
+	}
+
+	for _, f := range fields {
+		*f, err = check.Substitute(*f, vars)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := check.Substitute(string(r.Body), vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(r.Body) > 0 {
+		r.Body = []byte(body)
+	}
+
+	return r, nil
+}
+
+func stepsToAPI(steps []check.StepResult) []*api.StepResult {
+	res := make([]*api.StepResult, len(steps))
+	for i, s := range steps {
+		res[i] = &api.StepResult{
+			Name:        s.Name,
+			Status:      statusToAPI(s.Result.Status),
+			Message:     s.Result.Message,
+			Performance: performanceFromMetrics(s.Metrics),
+			Assertions:  assertionsToAPI(s.Result.Assertions),
+		}
+
+		if s.Response != nil {
+			res[i].StatusCode = uint32(s.Response.StatusCode)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestScenario(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1"})
+		fmt.Fprint(w, `{"token": "abc"}`)
+	})
+	mux.HandleFunc("/orders/abc", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil || r.Header.Get("X-Token") != "abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	host := strings.TrimPrefix(ts.URL, "http://")
+	step := func(name, path string) *api.Step {
+		return &api.Step{
+			Name: name,
+			Request: &api.Request{
+				Protocol:           "http",
+				Host:               host,
+				Path:               path,
+				Headers:            []*api.Header{{Name: "X-Token", Value: "{{token}}"}},
+				ExpectedStatusCode: []uint32{200},
+			},
+		}
+	}
+
+	login := step("login", "/login")
+	login.Request.Headers = nil
+	login.Extract = []*api.Extraction{{Name: "token", Source: api.Extraction_JSON_PATH, Expression: "$.token"}}
+
+	s := New(1, time.Second, time.Second, time.Second)
+	resp, err := s.Check(context.Background(), &api.Request{
+		CheckType: api.CheckType_SCENARIO,
+		Steps:     []*api.Step{login, step("orders", "/orders/{{token}}")},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+		assert.Len(t, resp.Steps, 2)
+		assert.Equal(t, "orders", resp.Steps[1].Name)
+		assert.Equal(t, uint32(200), resp.Steps[1].StatusCode)
+		assert.Equal(t, ts.URL+"/orders/abc", resp.FinalUrl)
+	}
+
+	resp, err = s.Check(context.Background(), &api.Request{
+		CheckType: api.CheckType_SCENARIO,
+		Steps:     []*api.Step{step("orders", "/orders/abc")},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_UNKNOWN, resp.Status)
+		assert.Equal(t, "Step 'orders' failed: Undefined variables: token", resp.Message)
+	}
+}
+
+func TestScenarioInvalid(t *testing.T) {
+	valid := &api.Request{Protocol: "http", Host: "example.com"}
+
+	tests := []struct {
+		name string
+		req  *api.Request
+	}{
+		{
+			name: "no steps",
+			req:  &api.Request{CheckType: api.CheckType_SCENARIO},
+		},
+		{
+			name: "assertion on scenario",
+			req: &api.Request{
+				CheckType:          api.CheckType_SCENARIO,
+				ExpectedStatusCode: []uint32{200},
+				Steps:              []*api.Step{{Request: valid}},
+			},
+		},
+		{
+			name: "TLS step",
+			req: &api.Request{
+				CheckType: api.CheckType_SCENARIO,
+				Steps:     []*api.Step{{Request: &api.Request{CheckType: api.CheckType_TLS, Host: "example.com:443"}}},
+			},
+		},
+		{
+			name: "invalid step",
+			req: &api.Request{
+				CheckType: api.CheckType_SCENARIO,
+				Steps:     []*api.Step{{Request: &api.Request{Protocol: "ftp", Host: "example.com"}}},
+			},
+		},
+		{
+			name: "invalid extraction",
+			req: &api.Request{
+				CheckType: api.CheckType_SCENARIO,
+				Steps: []*api.Step{{
+					Request: valid,
+					Extract: []*api.Extraction{{Name: "token", Source: api.Extraction_REGEX, Expression: "("}},
+				}},
+			},
+		},
+	}
+
+	s := New(1, time.Second, time.Second, time.Second)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := s.Check(context.Background(), test.req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
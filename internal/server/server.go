@@ -8,25 +8,67 @@ import (
 	"time"
 
 	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/config"
+	"github.com/MauveSoftware/http-check/pkg/check"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// Checker performs checks. It is implemented by HTTPCheckServer (using its worker pool)
+// and used to run checks within the server process (e.g. scheduled checks and probes).
+type Checker interface {
+	Check(ctx context.Context, req *api.Request) (*api.Response, error)
+}
+
 // HTTPCheckServer runs HTTP checks. It provides an gRPC interface to receive check tasks
 type HTTPCheckServer struct {
-	workerCount uint32
-	reqTimeout  time.Duration
-	tlsTimeout  time.Duration
-	ch          chan *task
+	workerCount  uint32
+	reqTimeout   time.Duration
+	tlsTimeout   time.Duration
+	queueTimeout time.Duration
+	maxBodySize  int64
+	cfg          *config.Config
+	clients      *clientCache
+	auth         map[string]check.Authenticator
+	ch           chan *task
+}
+
+// Option configures the server
+type Option func(*HTTPCheckServer)
+
+// WithMaxBodySize limits the number of body bytes buffered per check
+func WithMaxBodySize(n int64) Option {
+	return func(s *HTTPCheckServer) {
+		s.maxBodySize = n
+	}
+}
+
+// WithConfig defines the server configuration (e.g. named client certificates)
+func WithConfig(cfg *config.Config) Option {
+	return func(s *HTTPCheckServer) {
+		s.cfg = cfg
+	}
 }
 
 // New creates a new server instance
-func New(workerCount uint32, reqTimeout, tlsTimeout time.Duration) *HTTPCheckServer {
+func New(workerCount uint32, reqTimeout, tlsTimeout, queueTimeout time.Duration, opts ...Option) *HTTPCheckServer {
 	s := &HTTPCheckServer{
-		workerCount: workerCount,
-		reqTimeout:  reqTimeout,
-		tlsTimeout:  tlsTimeout,
-		ch:          make(chan *task),
+		workerCount:  workerCount,
+		reqTimeout:   reqTimeout,
+		tlsTimeout:   tlsTimeout,
+		queueTimeout: queueTimeout,
+		maxBodySize:  check.DefaultMaxBodySize,
+		cfg:          &config.Config{},
+		ch:           make(chan *task),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
 
+	s.clients = newClientCache(s.newHttpClient)
+	s.auth = s.newAuthenticators()
+
 	s.startWorkers()
 
 	return s
@@ -35,25 +77,26 @@ func New(workerCount uint32, reqTimeout, tlsTimeout time.Duration) *HTTPCheckSer
 func (s *HTTPCheckServer) startWorkers() {
 	for i := 0; i < int(s.workerCount); i++ {
 		w := &worker{
-			id:         i + 1,
-			cl:         s.newHttpClient(false),
-			insecureCl: s.newHttpClient(true),
-			ch:         s.ch,
+			id:          i + 1,
+			clients:     s.clients,
+			auth:        s.auth,
+			cfg:         s.cfg,
+			ch:          s.ch,
+			maxBodySize: s.maxBodySize,
+			timeout:     s.reqTimeout,
 		}
 		go w.run()
 	}
 }
 
-func (s *HTTPCheckServer) newHttpClient(insecure bool) *http.Client {
+func (s *HTTPCheckServer) newHttpClient(cfg *tls.Config) *http.Client {
 	var tr = &http.Transport{
-		Dial: (&net.Dialer{
+		DialContext: (&net.Dialer{
 			Timeout:       s.reqTimeout,
 			FallbackDelay: 100 * time.Millisecond,
-		}).Dial,
+		}).DialContext,
 		TLSHandshakeTimeout: s.tlsTimeout,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: insecure,
-		},
+		TLSClientConfig:     cfg,
 	}
 
 	return &http.Client{
@@ -61,14 +104,33 @@ func (s *HTTPCheckServer) newHttpClient(insecure bool) *http.Client {
 	}
 }
 
-// Check performs a http check and returns the check result
+// Check performs a http check and returns the check result.
+// Errors are returned as gRPC status errors to distinguish them from failed checks:
+// InvalidArgument (invalid request), Internal (unexpected error in worker),
+// DeadlineExceeded (no worker available within queue timeout or client deadline exceeded)
 func (s *HTTPCheckServer) Check(ctx context.Context, in *api.Request) (*api.Response, error) {
-	respCh := make(chan *api.Response, 1)
-	s.ch <- &task{
+	resCh := make(chan *taskResult, 1)
+	t := &task{
+		ctx: ctx,
 		req: in,
-		ch:  respCh,
+		ch:  resCh,
 	}
 
-	resp := <-respCh
-	return resp, nil
+	queueTimer := time.NewTimer(s.queueTimeout)
+	defer queueTimer.Stop()
+
+	select {
+	case s.ch <- t:
+	case <-queueTimer.C:
+		return nil, status.Errorf(codes.DeadlineExceeded, "No worker available within %v", s.queueTimeout)
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	select {
+	case res := <-resCh:
+		return res.resp, res.err
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
 }
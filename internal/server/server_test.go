@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/config"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckInvalidRequest(t *testing.T) {
+	s := New(1, time.Second, time.Second, time.Second)
+
+	_, err := s.Check(context.Background(), &api.Request{Protocol: "ftp", Host: "www.mauve.de"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.Check(context.Background(), &api.Request{Protocol: "https"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.Check(context.Background(), &api.Request{Protocol: "https", Host: "www.mauve.de", ResponseTimeWarning: "abc"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCheckQueueTimeout(t *testing.T) {
+	s := New(0, time.Second, time.Second, 10*time.Millisecond)
+
+	_, err := s.Check(context.Background(), &api.Request{Protocol: "https", Host: "www.mauve.de"})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func TestCheckContextCanceled(t *testing.T) {
+	s := New(0, time.Second, time.Second, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.Check(ctx, &api.Request{Protocol: "https", Host: "www.mauve.de"})
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestCheckContextCancelsRequest(t *testing.T) {
+	canceled := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(canceled)
+	}))
+	defer ts.Close()
+
+	s := New(1, time.Minute, time.Second, time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := s.Check(ctx, &api.Request{Protocol: "http", Host: strings.TrimPrefix(ts.URL, "http://")})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("request was not canceled")
+	}
+}
+
+func TestWorkerPanic(t *testing.T) {
+	clients := newClientCache(func(cfg *tls.Config) *http.Client {
+		panic("could not create client")
+	})
+	w := &worker{id: 1, clients: clients, cfg: &config.Config{}}
+
+	_, err := w.processTask(&task{ctx: context.Background(), req: &api.Request{Protocol: "https", Host: "www.mauve.de"}})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "could not create client")
+}
+
+func TestTLSCheck(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	s := New(1, time.Second, time.Second, time.Second)
+
+	resp, err := s.Check(context.Background(), &api.Request{
+		CheckType:           api.CheckType_TLS,
+		Host:                ts.Listener.Addr().String(),
+		ServerName:          "example.com",
+		Insecure:            true,
+		ExpectHostnameInSan: true,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+		assert.True(t, resp.Performance.HasCertificate)
+		assert.Equal(t, ts.Certificate().NotAfter.Unix(), resp.Performance.CertNotAfterSeconds)
+	}
+
+	_, err = s.Check(context.Background(), &api.Request{
+		CheckType:          api.CheckType_TLS,
+		Host:               ts.Listener.Addr().String(),
+		ExpectedStatusCode: []uint32{200},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.Check(context.Background(), &api.Request{
+		CheckType: api.CheckType_TLS,
+		Host:      "www.mauve.de",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCookies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ck, err := r.Cookie("consent"); err != nil || ck.Value != "1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1", HttpOnly: true})
+	}))
+	defer ts.Close()
+
+	s := New(1, time.Second, time.Second, time.Second)
+
+	resp, err := s.Check(context.Background(), &api.Request{
+		Protocol:           "http",
+		Host:               strings.TrimPrefix(ts.URL, "http://"),
+		CookieJar:          true,
+		Cookies:            []*api.Cookie{{Name: "consent", Value: "1"}},
+		ExpectedStatusCode: []uint32{200},
+		ExpectedCookies:    []string{"session;HttpOnly"},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+	}
+
+	_, err = s.Check(context.Background(), &api.Request{
+		Protocol:        "http",
+		Host:            strings.TrimPrefix(ts.URL, "http://"),
+		ExpectedCookies: []string{"session;Path=/"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.Check(context.Background(), &api.Request{
+		CheckType: api.CheckType_TLS,
+		Host:      "example.com:443",
+		CookieJar: true,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
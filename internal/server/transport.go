@@ -0,0 +1,219 @@
+package server
+
+import (
+	"container/list"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/MauveSoftware/http-check/internal/tlsutil"
+	"github.com/pkg/errors"
+)
+
+// clientKey identifies the TLS settings of a client
+type clientKey struct {
+	insecure bool
+	certFile string
+	keyFile  string
+	caFile   string
+	caPEM    string
+}
+
+// maxCachedClients is the maximum number of TLS settings clients are cached for.
+// The least recently used client is removed if the limit is exceeded (e.g. because of CA data sent by clients).
+const maxCachedClients = 128
+
+// clientCache shares HTTP clients (and their connection pools) between workers.
+// A client is created for each combination of TLS settings.
+type clientCache struct {
+	mu        sync.Mutex
+	entries   map[clientKey]*list.Element
+	lru       *list.List
+	max       int
+	newClient func(cfg *tls.Config) *http.Client
+}
+
+type cacheEntry struct {
+	key    clientKey
+	cfg    *tls.Config
+	client *http.Client
+}
+
+func newClientCache(newClient func(cfg *tls.Config) *http.Client) *clientCache {
+	return &clientCache{
+		entries:   make(map[clientKey]*list.Element),
+		lru:       list.New(),
+		max:       maxCachedClients,
+		newClient: newClient,
+	}
+}
+
+// get returns the client for the specified TLS settings
+func (c *clientCache) get(key clientKey) (*http.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, err := c.entry(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.client == nil {
+		e.client = c.newClient(e.cfg)
+	}
+
+	return e.client, nil
+}
+
+// getTLSConfig returns the TLS configuration for the specified TLS settings
+func (c *clientCache) getTLSConfig(key clientKey) (*tls.Config, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, err := c.entry(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.cfg, nil
+}
+
+// entry returns the (cached) entry for the TLS settings. The caller has to hold the lock.
+func (c *clientCache) entry(key clientKey) (*cacheEntry, error) {
+	if el, found := c.entries[key]; found {
+		c.lru.MoveToFront(el)
+		return el.Value.(*cacheEntry), nil
+	}
+
+	cfg, err := key.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &cacheEntry{key: key, cfg: cfg}
+	c.entries[key] = c.lru.PushFront(e)
+
+	if c.lru.Len() > c.max {
+		c.evict(c.lru.Back())
+	}
+
+	return e, nil
+}
+
+func (c *clientCache) evict(el *list.Element) {
+	e := c.lru.Remove(el).(*cacheEntry)
+	delete(c.entries, e.key)
+
+	if e.client != nil {
+		e.client.CloseIdleConnections()
+	}
+}
+
+// tlsConfig creates the TLS configuration for the TLS settings
+func (k clientKey) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: k.insecure,
+	}
+
+	if len(k.certFile) > 0 {
+		cert, err := newClientCertificate(k.certFile, k.keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.GetClientCertificate = cert.GetClientCertificate
+	}
+
+	roots, err := k.roots()
+	if err != nil {
+		return nil, err
+	}
+	cfg.RootCAs = roots
+
+	return cfg, nil
+}
+
+// roots returns the CA pool to verify certificates with (nil for the system roots)
+func (k clientKey) roots() (*x509.CertPool, error) {
+	if len(k.caFile) > 0 {
+		return tlsutil.LoadCertPool(k.caFile)
+	}
+
+	if len(k.caPEM) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(k.caPEM)) {
+			return nil, fmt.Errorf("No certificates found in CA data")
+		}
+
+		return pool, nil
+	}
+
+	return nil, nil
+}
+
+// clientCertificate is a client certificate loaded from files. It is reloaded when the files change (e.g. rotated certificates).
+type clientCertificate struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	version string
+}
+
+func newClientCertificate(certFile, keyFile string) (*clientCertificate, error) {
+	c := &clientCertificate{certFile: certFile, keyFile: keyFile}
+
+	_, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// GetClientCertificate returns the current certificate (used as tls.Config callback)
+func (c *clientCertificate) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return c.get()
+}
+
+func (c *clientCertificate) get() (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	version, err := fileVersion(c.certFile, c.keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load client certificate")
+	}
+
+	if c.cert != nil && version == c.version {
+		return c.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load client certificate")
+	}
+
+	c.cert, c.version = &cert, version
+	return c.cert, nil
+}
+
+// fileVersion returns a string changing when one of the files is modified
+func fileVersion(paths ...string) (string, error) {
+	b := strings.Builder{}
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(&b, "%d/%d;", fi.ModTime().UnixNano(), fi.Size())
+	}
+
+	return b.String(), nil
+}
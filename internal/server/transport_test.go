@@ -0,0 +1,325 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/config"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// writeKeyPair writes the certificate of the test server as client certificate to PEM files
+func writeKeyPair(t *testing.T, cert tls.Certificate) (string, string) {
+	dir := t.TempDir()
+
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600)
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0600)
+
+	return certFile, keyFile
+}
+
+func TestClientCertificate(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer other.Close()
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, strings.Replace(other.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+		}
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	certFile, keyFile := writeKeyPair(t, ts.TLS.Certificates[0])
+	authCertFile, authKeyFile := writeKeyPair(t, ts.TLS.Certificates[0])
+	cfg := &config.Config{
+		ClientCertificates: map[string]*config.ClientCertificate{
+			"api":  {CertFile: certFile, KeyFile: keyFile},
+			"mtls": {CertFile: authCertFile, KeyFile: authKeyFile},
+		},
+		Auth: map[string]*config.Auth{
+			"mtls": {AllowedHosts: []string{"127.0.0.1"}, CertFile: authCertFile, KeyFile: authKeyFile},
+		},
+	}
+	s := New(0, time.Second, time.Second, time.Second, WithConfig(cfg))
+	w := &worker{id: 1, clients: s.clients, cfg: s.cfg, maxBodySize: s.maxBodySize, timeout: s.reqTimeout}
+
+	newRequest := func() *api.Request {
+		return &api.Request{
+			Protocol:           "https",
+			Host:               strings.TrimPrefix(ts.URL, "https://"),
+			Insecure:           true,
+			ExpectedStatusCode: []uint32{200},
+		}
+	}
+
+	resp, err := w.processRequest(context.Background(), newRequest())
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_CRITICAL, resp.Status, "without certificate")
+	}
+
+	req := newRequest()
+	req.ClientCertName = "api"
+	resp, err = w.processRequest(context.Background(), req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+	}
+
+	req = newRequest()
+	req.ClientCertFile = certFile
+	req.ClientKeyFile = keyFile
+	resp, err = w.processRequest(context.Background(), req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+	}
+
+	req = newRequest()
+	req.AuthName = "mtls"
+	resp, err = w.processRequest(context.Background(), req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+	}
+
+	req.Path = "/redirect"
+	resp, err = w.processRequest(context.Background(), req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_CRITICAL, resp.Status, "redirect to other host")
+		assert.Contains(t, resp.Message, "Redirect to other host")
+	}
+
+	link := filepath.Join(t.TempDir(), "key.pem")
+	err = os.Symlink(authKeyFile, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req = newRequest()
+	req.ClientCertFile = certFile
+	req.ClientKeyFile = link
+	_, err = w.processRequest(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "files of auth")
+
+	req = newRequest()
+	req.ClientCertName = "mtls"
+	_, err = w.processRequest(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "client certificate name with files of auth")
+
+	req = newRequest()
+	req.CheckType = api.CheckType_TLS
+	req.ExpectedStatusCode = nil
+	req.AuthName = "mtls"
+	resp, err = w.processRequest(context.Background(), req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+	}
+
+	req.Host = strings.Replace(req.Host, "127.0.0.1", "localhost", 1)
+	_, err = w.processRequest(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "host not allowed")
+}
+
+func TestClientCertificateInvalid(t *testing.T) {
+	s := New(0, time.Second, time.Second, time.Second)
+	w := &worker{id: 1, clients: s.clients, cfg: s.cfg}
+
+	tests := []struct {
+		name string
+		req  *api.Request
+	}{
+		{
+			name: "unknown name",
+			req:  &api.Request{ClientCertName: "unknown"},
+		},
+		{
+			name: "name and file",
+			req:  &api.Request{ClientCertName: "api", ClientCertFile: "cert.pem", ClientKeyFile: "key.pem"},
+		},
+		{
+			name: "missing key file",
+			req:  &api.Request{ClientCertFile: "cert.pem"},
+		},
+		{
+			name: "file not found",
+			req:  &api.Request{ClientCertFile: "/nonexistent/cert.pem", ClientKeyFile: "/nonexistent/key.pem"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := w.clientForRequest(test.req)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestClientCacheReusesClients(t *testing.T) {
+	s := New(0, time.Second, time.Second, time.Second)
+
+	a, _ := s.clients.get(clientKey{})
+	b, _ := s.clients.get(clientKey{})
+	c, _ := s.clients.get(clientKey{insecure: true})
+
+	assert.Same(t, a, b)
+	assert.NotSame(t, a, c)
+}
+
+func TestClientCacheEviction(t *testing.T) {
+	s := New(0, time.Second, time.Second, time.Second)
+	s.clients.max = 2
+
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: newSelfSignedCertificate(t, "ca").Certificate[0]}))
+
+	a, _ := s.clients.get(clientKey{})
+	s.clients.get(clientKey{insecure: true})
+	b, _ := s.clients.get(clientKey{})
+	assert.Same(t, a, b)
+
+	_, err := s.clients.get(clientKey{caPEM: "invalid"})
+	assert.Error(t, err)
+	assert.Len(t, s.clients.entries, 2, "invalid settings are not cached")
+
+	s.clients.get(clientKey{caPEM: caPEM})
+	assert.Len(t, s.clients.entries, 2)
+	b, _ = s.clients.get(clientKey{})
+	assert.Same(t, a, b, "recently used client is kept")
+
+	s.clients.get(clientKey{insecure: true, caPEM: caPEM})
+	s.clients.get(clientKey{caPEM: caPEM})
+	b, _ = s.clients.get(clientKey{})
+	assert.NotSame(t, a, b, "least recently used client is evicted")
+}
+
+// newSelfSignedCertificate creates a certificate for the common name
+func newSelfSignedCertificate(t *testing.T, cn string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestClientCertificateRotation(t *testing.T) {
+	certFile, keyFile := writeKeyPair(t, newSelfSignedCertificate(t, "first"))
+
+	cfg, err := clientKey{certFile: certFile, keyFile: keyFile}.tlsConfig()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	commonName := func() string {
+		cert, err := cfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+		if !assert.NoError(t, err) {
+			return ""
+		}
+
+		c, _ := x509.ParseCertificate(cert.Certificate[0])
+		return c.Subject.CommonName
+	}
+	assert.Equal(t, "first", commonName())
+
+	rotatedCert, rotatedKey := writeKeyPair(t, newSelfSignedCertificate(t, "rotated"))
+	for _, f := range [][]string{{rotatedCert, certFile}, {rotatedKey, keyFile}} {
+		b, _ := ioutil.ReadFile(f[0])
+		ioutil.WriteFile(f[1], b, 0600)
+		modified := time.Now().Add(time.Minute)
+		os.Chtimes(f[1], modified, modified)
+	}
+	assert.Equal(t, "rotated", commonName())
+}
+
+func TestTrustStore(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ioutil.WriteFile(caFile, caPEM, 0600)
+
+	cfg := &config.Config{
+		TrustStores: map[string]*config.TrustStore{
+			"internal": {CAFile: caFile},
+		},
+	}
+	s := New(0, time.Second, time.Second, time.Second, WithConfig(cfg))
+	w := &worker{id: 1, clients: s.clients, cfg: s.cfg, maxBodySize: s.maxBodySize}
+
+	newRequest := func() *api.Request {
+		return &api.Request{
+			Protocol:           "https",
+			Host:               strings.TrimPrefix(ts.URL, "https://"),
+			ExpectedStatusCode: []uint32{200},
+		}
+	}
+
+	resp, err := w.processRequest(context.Background(), newRequest())
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_CRITICAL, resp.Status, "system roots")
+	}
+
+	req := newRequest()
+	req.TrustStoreName = "internal"
+	resp, err = w.processRequest(context.Background(), req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+	}
+
+	req = newRequest()
+	req.CaPem = caPEM
+	resp, err = w.processRequest(context.Background(), req)
+	if assert.NoError(t, err) {
+		assert.Equal(t, api.Status_OK, resp.Status, resp.Message)
+	}
+
+	req = newRequest()
+	req.TrustStoreName = "unknown"
+	_, err = w.processRequest(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	req = newRequest()
+	req.CaPem = []byte("invalid")
+	_, err = w.processRequest(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	req = newRequest()
+	req.ExpectedPins = []string{"invalid"}
+	_, err = w.processRequest(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -1,75 +1,323 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"runtime/debug"
 	"strings"
 	"time"
 
 	"github.com/MauveSoftware/http-check/internal/api"
+	"github.com/MauveSoftware/http-check/internal/config"
 	"github.com/MauveSoftware/http-check/pkg/check"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+var methodRegex = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
 type task struct {
+	ctx context.Context
 	req *api.Request
-	ch  chan<- *api.Response
+	ch  chan<- *taskResult
+}
+
+type taskResult struct {
+	resp *api.Response
+	err  error
 }
 
 type worker struct {
-	id         int
-	cl         *http.Client
-	insecureCl *http.Client
-	ch         chan *task
+	id          int
+	clients     *clientCache
+	auth        map[string]check.Authenticator
+	cfg         *config.Config
+	ch          chan *task
+	maxBodySize int64
+	timeout     time.Duration
 }
 
 func (w *worker) run() {
 	for t := range w.ch {
-		resp := w.processRequest(t.req)
-		t.ch <- resp
+		resp, err := w.processTask(t)
+		t.ch <- &taskResult{
+			resp: resp,
+			err:  err,
+		}
 	}
 }
 
-func (w *worker) processRequest(req *api.Request) *api.Response {
+func (w *worker) processTask(t *task) (resp *api.Response, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("#%d: Panic while processing check for %s: %v\n%s", w.id, t.req.Host, r, debug.Stack())
+			err = status.Errorf(codes.Internal, "Internal error while processing check: %v", r)
+		}
+	}()
+
+	return w.processRequest(t.ctx, t.req)
+}
+
+func (w *worker) processRequest(ctx context.Context, req *api.Request) (*api.Response, error) {
 	logrus.Infof("#%d: Processing check for %s", w.id, req.Host)
+	if req.CheckType == api.CheckType_SCENARIO {
+		return w.processScenario(ctx, req)
+	}
+
 	out := &strings.Builder{}
-	c := w.checkForRequest(req, out)
+	c, err := w.checkForRequest(req, out)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
-	start := time.Now()
-	err := c.Run()
+	res := c.RunContext(ctx)
 
-	if err != nil {
-		return &api.Response{
-			Success:      false,
-			Message:      err.Error(),
-			DebugMessage: out.String(),
+	resp := &api.Response{
+		Success:      res.Status == check.OK,
+		Status:       statusToAPI(res.Status),
+		Message:      res.Message,
+		DebugMessage: out.String(),
+		Performance:  performanceFromMetrics(c.Metrics()),
+		Assertions:   assertionsToAPI(res.Assertions),
+	}
+
+	if r := c.Response(); r != nil {
+		resp.FinalUrl = r.URL
+		resp.StatusCode = uint32(r.StatusCode)
+		resp.ContentLength = r.ContentLength
+		resp.Redirects = redirectsToAPI(r.Redirects)
+	}
+
+	return resp, nil
+}
+
+func redirectsToAPI(redirects []check.Redirect) []*api.Redirect {
+	res := make([]*api.Redirect, len(redirects))
+	for i, r := range redirects {
+		res[i] = &api.Redirect{
+			Url:        r.URL,
+			StatusCode: uint32(r.StatusCode),
+			Location:   r.Location,
 		}
 	}
 
-	return &api.Response{
-		Success:      true,
-		Message:      fmt.Sprintf("Request took %v", time.Since(start)),
-		DebugMessage: out.String(),
+	return res
+}
+
+func assertionsToAPI(assertions []check.AssertionResult) []*api.AssertionResult {
+	res := make([]*api.AssertionResult, len(assertions))
+	for i, a := range assertions {
+		res[i] = &api.AssertionResult{
+			Name:    a.Name,
+			Status:  statusToAPI(a.Status),
+			Message: a.Message,
+		}
 	}
+
+	return res
 }
 
-func (w *worker) checkForRequest(req *api.Request, out io.Writer) *check.Check {
+func statusToAPI(s check.Status) api.Status {
+	switch s {
+	case check.OK:
+		return api.Status_OK
+	case check.Warning:
+		return api.Status_WARNING
+	case check.Critical:
+		return api.Status_CRITICAL
+	default:
+		return api.Status_UNKNOWN
+	}
+}
+
+func performanceFromMetrics(m check.Metrics) *api.Performance {
+	p := &api.Performance{
+		DnsLookupSeconds:    m.DNSLookup.Seconds(),
+		ConnectSeconds:      m.Connect.Seconds(),
+		TlsHandshakeSeconds: m.TLSHandshake.Seconds(),
+		FirstByteSeconds:    m.FirstByte.Seconds(),
+		TotalSeconds:        m.Total.Seconds(),
+		BodySizeBytes:       m.BodySize,
+		HasCertificate:      m.HasCertificate(),
+	}
+
+	if p.HasCertificate {
+		p.CertExpireDays = m.CertExpireDays()
+		p.CertNotAfterSeconds = m.CertNotAfter.Unix()
+	}
+
+	return p
+}
+
+func (w *worker) checkForRequest(req *api.Request, out io.Writer) (*check.Check, error) {
+	if len(req.Host) == 0 {
+		return nil, fmt.Errorf("No host specified")
+	}
+
 	opts := []check.Option{}
 
-	if len(req.Username) > 0 {
-		opts = append(opts, check.WithBasicAuth(req.Username, req.Password))
+	if req.IncludeVerifiedChains {
+		opts = append(opts, check.WithVerifiedChains())
 	}
 
 	if req.Debug {
 		opts = append(opts, check.WithDebug(out))
 	}
 
-	url := fmt.Sprintf("%s://%s%s", req.Protocol, req.Host, req.Path)
+	var c *check.Check
+	var err error
+	switch req.CheckType {
+	case api.CheckType_HTTP:
+		c, err = w.httpCheckForRequest(req, opts)
+	case api.CheckType_TLS:
+		c, err = w.tlsCheckForRequest(req, opts)
+	default:
+		return nil, fmt.Errorf("Unsupported check type %v", req.CheckType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CertExpireDays > 0 {
+		c.AssertCertificateExpireDays(time.Duration(req.CertExpireDays) * 24 * time.Hour)
+	}
+
+	if len(req.ExpectedPins) > 0 {
+		pins := make([]*check.Pin, len(req.ExpectedPins))
+		for i, p := range req.ExpectedPins {
+			pins[i], err = check.ParsePin(p)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		c.AssertCertificatePin(pins)
+	}
+
+	err = addTLSAssertions(c, req)
+	if err != nil {
+		return nil, err
+	}
+
+	timeWarning, timeCritical, err := parseThresholds(req.ResponseTimeWarning, req.ResponseTimeCritical)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid response time threshold")
+	}
+
+	if timeWarning != nil || timeCritical != nil {
+		c.AssertResponseTime(timeWarning, timeCritical)
+	}
+
+	certWarning, certCritical, err := parseThresholds(req.CertExpireWarning, req.CertExpireCritical)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid certificate expiration threshold")
+	}
+
+	if certWarning != nil || certCritical != nil {
+		c.AssertCertificateExpireThresholds(certWarning, certCritical)
+	}
+
+	return c, nil
+}
+
+func (w *worker) httpCheckForRequest(req *api.Request, opts []check.Option) (*check.Check, error) {
+	if len(req.Starttls) > 0 {
+		return nil, fmt.Errorf("STARTTLS is only supported by TLS checks")
+	}
+
+	if req.Protocol != "http" && req.Protocol != "https" {
+		return nil, fmt.Errorf("Unsupported protocol '%s'", req.Protocol)
+	}
+
+	opts = append(opts, check.WithMaxBodySize(w.maxBodySize), check.WithTimeout(w.timeout))
+
+	if len(req.Method) > 0 {
+		if !methodRegex.MatchString(req.Method) {
+			return nil, fmt.Errorf("Invalid method '%s'", req.Method)
+		}
+
+		opts = append(opts, check.WithMethod(req.Method))
+	}
+
+	if len(req.Body) > 0 {
+		opts = append(opts, check.WithRequestBody(req.Body))
+	}
+
+	if req.RedirectMode == api.RedirectMode_NO_FOLLOW {
+		opts = append(opts, check.WithoutRedirects())
+	} else if req.MaxRedirects > 0 {
+		opts = append(opts, check.WithMaxRedirects(int(req.MaxRedirects)))
+	}
+
+	for _, h := range req.Headers {
+		if len(h.Name) == 0 {
+			return nil, fmt.Errorf("Header name must not be empty")
+		}
+
+		opts = append(opts, check.WithHeader(h.Name, h.Value))
+	}
+
+	if len(req.Username) > 0 && len(req.AuthName) > 0 {
+		return nil, fmt.Errorf("Basic auth and auth name can not be used together")
+	}
+
+	auth, err := w.authForRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.AuthScheme) > 0 && len(req.Username) == 0 && (auth == nil || auth.Type != config.AuthBasic) {
+		return nil, fmt.Errorf("Auth scheme requires a username")
+	}
+
+	if len(req.Username) > 0 {
+		a, err := check.PasswordAuth(req.AuthScheme, req.Username, req.Password)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, check.WithAuthenticator(a))
+	}
+
+	if auth != nil {
+		a, err := w.authenticatorForRequest(req, auth)
+		if err != nil {
+			return nil, err
+		}
+
+		if a != nil {
+			opts = append(opts, check.WithAuthenticator(a))
+		}
+
+		// the client certificate is part of the transport and would be presented to any redirect target
+		if len(auth.CertFile) > 0 {
+			opts = append(opts, check.WithoutRedirectsToOtherHosts())
+		}
+	}
+
+	if req.CookieJar {
+		opts = append(opts, check.WithCookieJar())
+	}
+
+	for _, ck := range req.Cookies {
+		if len(ck.Name) == 0 {
+			return nil, fmt.Errorf("Cookie name must not be empty")
+		}
 
-	cl := w.cl
-	if req.Insecure {
-		cl = w.insecureCl
+		opts = append(opts, check.WithCookie(ck.Name, ck.Value))
+	}
+
+	url := requestURL(req)
+
+	cl, err := w.clientForRequest(req)
+	if err != nil {
+		return nil, err
 	}
 
 	c := check.NewCheck(cl, url, opts...)
@@ -78,6 +326,25 @@ func (w *worker) checkForRequest(req *api.Request, out io.Writer) *check.Check {
 		c.AssertStatusCodeIn(req.ExpectedStatusCode)
 	}
 
+	if len(req.ExpectedFinalUrl) > 0 {
+		c.AssertFinalURL(req.ExpectedFinalUrl)
+	}
+
+	if len(req.ExpectedLocation) > 0 {
+		c.AssertLocation(req.ExpectedLocation)
+	}
+
+	if len(req.ExpectedRedirectChain) > 0 {
+		c.AssertRedirectChain(req.ExpectedRedirectChain)
+	}
+
+	for _, a := range req.ExpectedHeaders {
+		err := addHeaderAssertion(c, a)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid header assertion for %s", a.Name)
+		}
+	}
+
 	if len(req.ExpectedBody) > 0 {
 		c.AssertBodyContains(req.ExpectedBody)
 	}
@@ -86,9 +353,178 @@ func (w *worker) checkForRequest(req *api.Request, out io.Writer) *check.Check {
 		c.AssertBodyMatches(req.ExpectedBodyRegex)
 	}
 
-	if req.CertExpireDays > 0 {
-		c.AssertCertificateExpireDays(time.Duration(req.CertExpireDays) * 24 * time.Hour)
+	for _, a := range req.ExpectedJson {
+		err := addJSONPathAssertion(c, a)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid JSON assertion for %s", a.Path)
+		}
+	}
+
+	for _, a := range req.ExpectedSelectors {
+		err := addSelectorAssertion(c, a)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid selector assertion for %s", a.Selector)
+		}
+	}
+
+	for _, s := range req.ExpectedCookies {
+		e, err := check.ParseCookieExpectation(s)
+		if err != nil {
+			return nil, err
+		}
+
+		c.AssertCookie(e)
+	}
+
+	return c, nil
+}
+
+// requestURL returns the URL requested by a HTTP check
+func requestURL(req *api.Request) string {
+	return fmt.Sprintf("%s://%s%s", req.Protocol, req.Host, req.Path)
+}
+
+func (w *worker) tlsCheckForRequest(req *api.Request, opts []check.Option) (*check.Check, error) {
+	if hasHTTPAssertions(req) {
+		return nil, fmt.Errorf("HTTP assertions are not supported by TLS checks")
+	}
+
+	if req.CookieJar || len(req.Cookies) > 0 {
+		return nil, fmt.Errorf("Cookies are not supported by TLS checks")
+	}
+
+	if len(req.AuthScheme) > 0 {
+		return nil, fmt.Errorf("Authentication is not supported by TLS checks")
+	}
+
+	auth, err := w.authForRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if auth != nil && auth.HasHTTPAuth() {
+		return nil, fmt.Errorf("Auth '%s' contains HTTP authentication which is not supported by TLS checks", req.AuthName)
+	}
+
+	key, err := w.clientKeyForRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := w.clients.getTLSConfig(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Starttls) > 0 {
+		p, err := check.ParseStartTLS(req.Starttls)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, check.WithStartTLS(p))
+	}
+
+	return check.NewTLSCheck(req.Host, req.ServerName, cfg, w.timeout, opts...)
+}
+
+func hasHTTPAssertions(req *api.Request) bool {
+	return len(req.ExpectedStatusCode) > 0 ||
+		len(req.ExpectedBody) > 0 ||
+		len(req.ExpectedBodyRegex) > 0 ||
+		len(req.ExpectedHeaders) > 0 ||
+		len(req.ExpectedJson) > 0 ||
+		len(req.ExpectedSelectors) > 0 ||
+		len(req.ExpectedFinalUrl) > 0 ||
+		len(req.ExpectedLocation) > 0 ||
+		len(req.ExpectedRedirectChain) > 0 ||
+		len(req.ExpectedCookies) > 0
+}
+
+// clientForRequest returns the HTTP client matching the TLS settings of the request
+func (w *worker) clientForRequest(req *api.Request) (*http.Client, error) {
+	key, err := w.clientKeyForRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.clients.get(key)
+}
+
+// clientKeyForRequest resolves the TLS settings of the request
+func (w *worker) clientKeyForRequest(req *api.Request) (clientKey, error) {
+	key := clientKey{
+		insecure: req.Insecure,
+		certFile: req.ClientCertFile,
+		keyFile:  req.ClientKeyFile,
+		caPEM:    string(req.CaPem),
+	}
+
+	if len(req.ClientCertName) > 0 {
+		if len(key.certFile) > 0 || len(key.keyFile) > 0 {
+			return key, fmt.Errorf("Client certificate name and files can not be used together")
+		}
+
+		cert, found := w.cfg.ClientCertificates[req.ClientCertName]
+		if !found {
+			return key, fmt.Errorf("Unknown client certificate '%s'", req.ClientCertName)
+		}
+
+		key.certFile, key.keyFile = cert.CertFile, cert.KeyFile
+	}
+
+	// the certificate of an auth must only be presented to the allowed hosts
+	if w.cfg.IsAuthFile(key.certFile) || w.cfg.IsAuthFile(key.keyFile) {
+		return key, fmt.Errorf("Client certificate of an auth can only be used by referencing the auth")
+	}
+
+	auth, err := w.authForRequest(req)
+	if err != nil {
+		return key, err
+	}
+
+	if auth != nil && len(auth.CertFile) > 0 {
+		if len(key.certFile) > 0 || len(key.keyFile) > 0 {
+			return key, fmt.Errorf("Auth with client certificate and client certificate name or files can not be used together")
+		}
+
+		key.certFile, key.keyFile = auth.CertFile, auth.KeyFile
+	}
+
+	if (len(key.certFile) > 0) != (len(key.keyFile) > 0) {
+		return key, fmt.Errorf("Client certificate and key file have to be specified together")
+	}
+
+	if len(req.TrustStoreName) > 0 {
+		if len(key.caPEM) > 0 {
+			return key, fmt.Errorf("Trust store name and CA data can not be used together")
+		}
+
+		ts, found := w.cfg.TrustStores[req.TrustStoreName]
+		if !found {
+			return key, fmt.Errorf("Unknown trust store '%s'", req.TrustStoreName)
+		}
+
+		key.caFile = ts.CAFile
+	}
+
+	return key, nil
+}
+
+func parseThresholds(warning, critical string) (w *check.Range, c *check.Range, err error) {
+	if len(warning) > 0 {
+		w, err = check.ParseRange(warning)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(critical) > 0 {
+		c, err = check.ParseRange(critical)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	return c
+	return w, c, nil
 }
@@ -0,0 +1,79 @@
+// Package tlsutil provides helpers to create TLS configurations from PEM files
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// LoadCertPool creates a certificate pool containing the PEM encoded certificates of the file
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read CA file")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("No certificates found in %s", path)
+	}
+
+	return pool, nil
+}
+
+// ServerConfig creates a TLS configuration for a server. If clientCAPath is specified, clients have to
+// authenticate with a certificate issued by one of the CAs in this file.
+func ServerConfig(certPath, keyPath, clientCAPath string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not load server certificate")
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if len(clientCAPath) > 0 {
+		cfg.ClientCAs, err = LoadCertPool(clientCAPath)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, nil
+}
+
+// ClientConfig creates a TLS configuration for a client. If caPath is specified, the server certificate is verified
+// against the CAs in this file instead of the system roots. If certPath and keyPath are specified the client
+// authenticates with this certificate.
+func ClientConfig(caPath, certPath, keyPath string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	var err error
+	if len(caPath) > 0 {
+		cfg.RootCAs, err = LoadCertPool(caPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(certPath) > 0 || len(keyPath) > 0 {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "Could not load client certificate")
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
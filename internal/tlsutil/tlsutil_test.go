@@ -0,0 +1,224 @@
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	dir  string
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, _ := x509.ParseCertificate(der)
+	ca := &testCA{cert: cert, key: key, dir: t.TempDir()}
+	writePEM(t, ca.path(name+".pem"), "CERTIFICATE", der)
+
+	return ca
+}
+
+func (ca *testCA) path(name string) string {
+	return filepath.Join(ca.dir, name)
+}
+
+// issue creates a certificate signed by the CA and returns the paths of the certificate and key file
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writePEM(t, ca.path(name+".crt"), "CERTIFICATE", der)
+	writePEM(t, ca.path(name+".key"), "EC PRIVATE KEY", keyDER)
+
+	return ca.path(name + ".crt"), ca.path(name + ".key")
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func handshake(t *testing.T, serverCfg, clientCfg *tls.Config) error {
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if conn.(*tls.Conn).Handshake() == nil {
+			conn.Write([]byte{1})
+		}
+	}()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	c := tls.Client(conn, clientCfg)
+	err = c.Handshake()
+	if err != nil {
+		return err
+	}
+
+	// with TLS 1.3 a rejected client certificate is only reported on the first read
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = c.Read(make([]byte, 1))
+	return err
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	otherCA := newTestCA(t, "other")
+	serverCert, serverKey := ca.issue(t, "localhost", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, "client", x509.ExtKeyUsageClientAuth)
+	otherCert, otherKey := otherCA.issue(t, "other-client", x509.ExtKeyUsageClientAuth)
+
+	tests := []struct {
+		name      string
+		clientCA  string
+		certPath  string
+		keyPath   string
+		wantError bool
+	}{
+		{
+			name:     "no client auth required",
+			clientCA: "",
+		},
+		{
+			name:     "valid client certificate",
+			clientCA: ca.path("ca.pem"),
+			certPath: clientCert,
+			keyPath:  clientKey,
+		},
+		{
+			name:      "missing client certificate",
+			clientCA:  ca.path("ca.pem"),
+			wantError: true,
+		},
+		{
+			name:      "client certificate of unknown CA",
+			clientCA:  ca.path("ca.pem"),
+			certPath:  otherCert,
+			keyPath:   otherKey,
+			wantError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			serverCfg, err := ServerConfig(serverCert, serverKey, test.clientCA)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			clientCfg, err := ClientConfig(ca.path("ca.pem"), test.certPath, test.keyPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clientCfg.ServerName = "localhost"
+
+			err = handshake(t, serverCfg, clientCfg)
+			if test.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServerCertificateOfUnknownCA(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	otherCA := newTestCA(t, "other")
+	serverCert, serverKey := ca.issue(t, "localhost", x509.ExtKeyUsageServerAuth)
+
+	serverCfg, err := ServerConfig(serverCert, serverKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientCfg, err := ClientConfig(otherCA.path("other.pem"), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCfg.ServerName = "localhost"
+
+	assert.Error(t, handshake(t, serverCfg, clientCfg))
+}
+
+func TestLoadCertPoolWithoutCertificates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.pem")
+	if err := ioutil.WriteFile(path, []byte("no certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadCertPool(path)
+	assert.Error(t, err)
+}
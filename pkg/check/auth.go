@@ -0,0 +1,155 @@
+package check
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Auth schemes for username/password authentication
+const (
+	AuthSchemeBasic  = "basic"
+	AuthSchemeDigest = "digest"
+	AuthSchemeNTLM   = "ntlm"
+)
+
+// Authenticator adds credentials to the requests of a check
+type Authenticator interface {
+	// Authenticate adds the credentials to the request
+	Authenticate(req *http.Request) error
+}
+
+// RoundTripAuthenticator is an authenticator performing a challenge/response flow (e.g. Digest or NTLM)
+type RoundTripAuthenticator interface {
+	Authenticator
+
+	// RoundTripper wraps the transport used to send the requests of a check run
+	RoundTripper(next http.RoundTripper) http.RoundTripper
+}
+
+// WithAuthenticator defines how the requests of the check are authenticated
+func WithAuthenticator(a Authenticator) Option {
+	return func(c *Check) {
+		c.auth = a
+	}
+}
+
+// PasswordAuth returns the authenticator for username/password authentication using the scheme (default: basic)
+func PasswordAuth(scheme, username, password string) (Authenticator, error) {
+	switch strings.ToLower(scheme) {
+	case "", AuthSchemeBasic:
+		return BasicAuth(username, password), nil
+	case AuthSchemeDigest:
+		return DigestAuth(username, password), nil
+	case AuthSchemeNTLM:
+		return NTLMAuth(username, password), nil
+	default:
+		return nil, fmt.Errorf("Unsupported auth scheme '%s' (expected %s, %s or %s)", scheme, AuthSchemeBasic, AuthSchemeDigest, AuthSchemeNTLM)
+	}
+}
+
+type basicAuth struct {
+	username string
+	password string
+}
+
+// BasicAuth authenticates requests using HTTP basic auth
+func BasicAuth(username, password string) Authenticator {
+	return &basicAuth{username: username, password: password}
+}
+
+func (a *basicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+type headerAuth struct {
+	name  string
+	value string
+}
+
+// BearerToken authenticates requests by sending a static token in the Authorization header
+func BearerToken(token string) Authenticator {
+	return &headerAuth{name: "Authorization", value: "Bearer " + token}
+}
+
+// APIKey authenticates requests by sending a key in the specified header
+func APIKey(header, key string) Authenticator {
+	return &headerAuth{name: header, value: key}
+}
+
+func (a *headerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set(a.name, a.value)
+	return nil
+}
+
+// authenticate adds the credentials to the request and returns the names of the headers set by the authenticator
+func (c *Check) authenticate(req *http.Request) ([]string, error) {
+	before := req.Header.Clone()
+
+	err := c.auth.Authenticate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for name, values := range req.Header {
+		if !reflect.DeepEqual(before[name], values) {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// challenges returns the parameters of all challenges of the scheme sent by the server
+func challenges(resp *http.Response, scheme string) []string {
+	res := []string{}
+	for _, v := range resp.Header.Values("WWW-Authenticate") {
+		if strings.EqualFold(v, scheme) {
+			res = append(res, "")
+			continue
+		}
+
+		if len(v) > len(scheme) && strings.EqualFold(v[:len(scheme)+1], scheme+" ") {
+			res = append(res, strings.TrimSpace(v[len(scheme)+1:]))
+		}
+	}
+
+	return res
+}
+
+// resendRequest returns a copy of the request to send it again (e.g. with credentials after a challenge)
+func resendRequest(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return r, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("Request body can not be sent again")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = body
+
+	return r, nil
+}
+
+// discardResponse reads and closes the body, so the connection can be reused
+func discardResponse(resp *http.Response) {
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
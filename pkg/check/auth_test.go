@@ -0,0 +1,133 @@
+package check
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func echoAuthServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s|%s", r.Header.Get("Authorization"), r.Header.Get("X-Api-Key"))
+	}))
+}
+
+func TestBearerTokenAndAPIKey(t *testing.T) {
+	s := echoAuthServer()
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL, WithAuthenticator(BearerToken("t0k3n")))
+	c.AssertBodyContains("Bearer t0k3n|")
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+
+	c = NewCheck(s.Client(), s.URL, WithAuthenticator(APIKey("X-Api-Key", "k3y")))
+	c.AssertBodyContains("|k3y")
+	res = c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+}
+
+func TestAuthRedirectToOtherHost(t *testing.T) {
+	other := echoAuthServer()
+	defer other.Close()
+
+	digest := digestServer(t, `Digest realm="test", qop="auth", nonce="abc"`)
+	defer digest.Close()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/other":
+			http.Redirect(w, r, other.URL, http.StatusFound)
+		case "/digest":
+			http.Redirect(w, r, digest.URL, http.StatusFound)
+		case "/same":
+			http.Redirect(w, r, "/echo", http.StatusFound)
+		default:
+			fmt.Fprintf(w, "%s|%s", r.Header.Get("Authorization"), r.Header.Get("X-Api-Key"))
+		}
+	}))
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL+"/same", WithAuthenticator(APIKey("X-Api-Key", "k3y")))
+	c.Run()
+	assert.Equal(t, "|k3y", string(c.Response().Body), "same host")
+
+	c = NewCheck(s.Client(), s.URL+"/other", WithAuthenticator(APIKey("X-Api-Key", "k3y")))
+	c.Run()
+	assert.Equal(t, "|", string(c.Response().Body), "other host")
+
+	c = NewCheck(s.Client(), s.URL+"/digest", WithAuthenticator(DigestAuth("monitoring", "s3cr3t")))
+	c.Run()
+	assert.Equal(t, http.StatusUnauthorized, c.Response().StatusCode, "challenge of other host")
+}
+
+func tokenServer(t *testing.T, expiresIn int, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		id, secret, _ := r.BasicAuth()
+		if id != "monitoring" || secret != "s3cr3t" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "invalid_client"}`)
+			return
+		}
+
+		assert.Equal(t, "read write", r.FormValue("scope"))
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": %d}`, atomic.LoadInt32(requests), expiresIn)
+	}))
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var requests int32
+	ts := tokenServer(t, 3600, &requests)
+	defer ts.Close()
+
+	s := echoAuthServer()
+	defer s.Close()
+
+	o := NewOAuth2ClientCredentials(ts.Client(), ts.URL, "monitoring", "s3cr3t", []string{"read", "write"})
+	for i := 0; i < 3; i++ {
+		c := NewCheck(s.Client(), s.URL, WithAuthenticator(o))
+		c.AssertBodyContains("Bearer token-1|")
+		res := c.Run()
+		assert.Equal(t, OK, res.Status, res.Message)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "token is cached")
+}
+
+func TestOAuth2ClientCredentialsWithoutLifetime(t *testing.T) {
+	var requests int32
+	ts := tokenServer(t, 0, &requests)
+	defer ts.Close()
+
+	s := echoAuthServer()
+	defer s.Close()
+
+	o := NewOAuth2ClientCredentials(ts.Client(), ts.URL, "monitoring", "s3cr3t", []string{"read", "write"})
+	for i := 0; i < 2; i++ {
+		c := NewCheck(s.Client(), s.URL, WithAuthenticator(o))
+		c.Run()
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests), "token is not cached")
+}
+
+func TestOAuth2ClientCredentialsInvalidClient(t *testing.T) {
+	var requests int32
+	ts := tokenServer(t, 3600, &requests)
+	defer ts.Close()
+
+	s := echoAuthServer()
+	defer s.Close()
+
+	o := NewOAuth2ClientCredentials(ts.Client(), ts.URL, "monitoring", "wrong", nil)
+	c := NewCheck(s.Client(), s.URL, WithAuthenticator(o))
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, `Authentication failed: Could not get OAuth2 token: Token endpoint returned 401 Unauthorized: {"error": "invalid_client"}`, res.Message)
+}
@@ -0,0 +1,119 @@
+package check
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// AssertCertificateExpireDays tests the days until expiration of the certificate chain presented by the server
+func (c *Check) AssertCertificateExpireDays(d time.Duration) {
+	c.addAssertion(fmt.Sprintf("Certificate valid for at least %v days", d.Hours()/24), func(r *Response) error {
+		first, err := c.firstExpiringCertificate(r)
+		if err != nil {
+			return err
+		}
+
+		min := time.Now().Add(d)
+		if !first.NotAfter.After(min) {
+			return fmt.Errorf("%s expires on %v (%v days left)", describeCertificate(first), first.NotAfter, expireDays(first))
+		}
+
+		return nil
+	})
+}
+
+// AssertCertificateExpireThresholds tests the days until expiration of the certificate chain presented by the server
+// against warning and critical thresholds (nil for none)
+func (c *Check) AssertCertificateExpireThresholds(warning, critical *Range) {
+	t := thresholds{
+		warning:  warning,
+		critical: critical,
+	}
+
+	c.addAssertion(t.describe("Certificate expiration"), func(r *Response) error {
+		first, err := c.firstExpiringCertificate(r)
+		if err != nil {
+			return err
+		}
+
+		return t.evaluate(expireDays(first), expireFormat(first))
+	})
+}
+
+// AssertIntermediateExpireThresholds tests the days until expiration of the intermediate certificates
+// presented by the server against warning and critical thresholds (nil for none)
+func (c *Check) AssertIntermediateExpireThresholds(warning, critical *Range) {
+	t := thresholds{
+		warning:  warning,
+		critical: critical,
+	}
+
+	c.addAssertion(t.describe("Intermediate certificate expiration"), func(r *Response) error {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) < 2 {
+			return nil
+		}
+
+		first := firstExpiring(r.TLS.PeerCertificates[1:])
+		return t.evaluate(expireDays(first), expireFormat(first))
+	})
+}
+
+// firstExpiringCertificate returns the certificate of the presented chain (and the verified chains if enabled)
+// which expires first
+func (c *Check) firstExpiringCertificate(r *Response) (*x509.Certificate, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("No certificate returned")
+	}
+
+	return firstExpiringInState(r.TLS, c.verifiedChains), nil
+}
+
+// firstExpiringInState returns the certificate of the presented chain (and the verified chains if requested)
+// which expires first (nil if no certificate was presented)
+func firstExpiringInState(state *tls.ConnectionState, verifiedChains bool) *x509.Certificate {
+	certs := append([]*x509.Certificate{}, state.PeerCertificates...)
+	if verifiedChains {
+		for _, chain := range state.VerifiedChains {
+			certs = append(certs, chain...)
+		}
+	}
+
+	return firstExpiring(certs)
+}
+
+func firstExpiring(certs []*x509.Certificate) *x509.Certificate {
+	var first *x509.Certificate
+	for _, cert := range certs {
+		if first == nil || cert.NotAfter.Before(first.NotAfter) {
+			first = cert
+		}
+	}
+
+	return first
+}
+
+func expireDays(cert *x509.Certificate) float64 {
+	return math.Floor(time.Until(cert.NotAfter).Hours() / 24)
+}
+
+// expireFormat returns the message format for threshold evaluation of the expiration of the certificate
+func expireFormat(cert *x509.Certificate) string {
+	return escapeFormat(describeCertificate(cert)) + " expires in %v days"
+}
+
+func describeCertificate(cert *x509.Certificate) string {
+	subject := cert.Subject.String()
+	if len(subject) == 0 {
+		return "Certificate"
+	}
+
+	return fmt.Sprintf("Certificate '%s'", subject)
+}
+
+func escapeFormat(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
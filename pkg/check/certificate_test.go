@@ -0,0 +1,81 @@
+package check
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func certificateExpiringIn(cn string, days int) *x509.Certificate {
+	return &x509.Certificate{
+		Subject:  pkix.Name{CommonName: cn},
+		NotAfter: time.Now().Add(time.Duration(days)*24*time.Hour + time.Hour),
+	}
+}
+
+func TestCertificateExpirationWalksChain(t *testing.T) {
+	resp := &Response{
+		TLS: &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{
+				certificateExpiringIn("www.mauve.de", 60),
+				certificateExpiringIn("Intermediate CA", 10),
+			},
+		},
+	}
+
+	c := NewCheck(nil, "")
+	c.AssertCertificateExpireThresholds(mustParseRange("30:"), mustParseRange("14:"))
+	c.AssertCertificateExpireDays(14 * 24 * time.Hour)
+
+	res := newResult(c.validate(resp))
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Certificate 'CN=Intermediate CA' expires in 10 days (critical threshold: 14:)", res.Assertions[0].Message)
+	assert.Contains(t, res.Assertions[1].Message, "Certificate 'CN=Intermediate CA' expires on")
+	assert.Contains(t, res.Assertions[1].Message, "(10 days left)")
+}
+
+func TestCertificateExpirationWithVerifiedChains(t *testing.T) {
+	leaf := certificateExpiringIn("www.mauve.de", 60)
+	resp := &Response{
+		TLS: &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{leaf},
+			VerifiedChains: [][]*x509.Certificate{
+				{leaf, certificateExpiringIn("Root CA", 20)},
+			},
+		},
+	}
+
+	c := NewCheck(nil, "")
+	c.AssertCertificateExpireThresholds(mustParseRange("30:"), nil)
+	res := newResult(c.validate(resp))
+	assert.Equal(t, OK, res.Status, "presented chain only")
+
+	c = NewCheck(nil, "", WithVerifiedChains())
+	c.AssertCertificateExpireThresholds(mustParseRange("30:"), nil)
+	res = newResult(c.validate(resp))
+	assert.Equal(t, Warning, res.Status)
+	assert.Equal(t, "Certificate 'CN=Root CA' expires in 20 days (warning threshold: 30:)", res.Message)
+}
+
+func TestRecordCertificateUsesFirstExpiring(t *testing.T) {
+	leaf := certificateExpiringIn("www.mauve.de", 60)
+	intermediate := certificateExpiringIn("Intermediate CA", 40)
+	root := certificateExpiringIn("Root CA", 20)
+	state := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{leaf, intermediate},
+		VerifiedChains:   [][]*x509.Certificate{{leaf, intermediate, root}},
+	}
+
+	m := Metrics{}
+	m.recordCertificate(state, false)
+	assert.Equal(t, intermediate.NotAfter, m.CertNotAfter)
+
+	m.recordCertificate(state, true)
+	assert.Equal(t, root.NotAfter, m.CertNotAfter)
+	assert.Equal(t, float64(20), math.Floor(m.CertExpireDays()))
+}
@@ -1,10 +1,13 @@
 package check
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptrace"
 	"regexp"
 	"strings"
 	"time"
@@ -17,9 +20,51 @@ type Option func(*Check)
 
 // WithBasicAuth defines basic auth parameters used by the check
 func WithBasicAuth(username, password string) Option {
+	return WithAuthenticator(BasicAuth(username, password))
+}
+
+// WithMethod defines the HTTP method used for the request (default: GET)
+func WithMethod(method string) Option {
+	return func(c *Check) {
+		c.method = method
+	}
+}
+
+// WithRequestBody defines the body sent with the request
+func WithRequestBody(body []byte) Option {
 	return func(c *Check) {
-		c.username = username
-		c.password = password
+		c.requestBody = body
+	}
+}
+
+// WithHeader adds a header to the request. The Host header overrides the host sent to the server.
+func WithHeader(name, value string) Option {
+	return func(c *Check) {
+		c.headers = append(c.headers, header{
+			name:  name,
+			value: value,
+		})
+	}
+}
+
+// WithMaxBodySize limits the number of body bytes buffered for assertions
+func WithMaxBodySize(n int64) Option {
+	return func(c *Check) {
+		c.maxBodySize = n
+	}
+}
+
+// WithTimeout limits the time for the whole request including authentication, redirects and reading the body
+func WithTimeout(d time.Duration) Option {
+	return func(c *Check) {
+		c.timeout = d
+	}
+}
+
+// WithVerifiedChains includes the verified chains (e.g. the root certificate) in certificate expiration assertions
+func WithVerifiedChains() Option {
+	return func(c *Check) {
+		c.verifiedChains = true
 	}
 }
 
@@ -35,20 +80,51 @@ func WithDebug(w io.Writer) Option {
 type Check struct {
 	client      *http.Client
 	url         string
-	username    string
-	password    string
+	method      string
+	requestBody []byte
+	headers     []header
+	auth        Authenticator
 	assertions  []assertion
 	debug       bool
 	debugWriter io.Writer
+	metrics     Metrics
+	maxBodySize int64
+	timeout     time.Duration
+	response    *Response
+
+	followRedirects   bool
+	maxRedirects      int
+	sameHostRedirects bool
+	verifiedChains    bool
+
+	tlsTarget *tlsTarget
+	startTLS  StartTLS
+
+	cookieJar bool
+	cookies   []*http.Cookie
+	jar       http.CookieJar
 }
 
-type assertion func(*http.Response) error
+type assertion struct {
+	name string
+	fn   func(*Response) error
+}
+
+type header struct {
+	name  string
+	value string
+}
 
 // NewCheck creates a new Check instance
 func NewCheck(client *http.Client, url string, opts ...Option) *Check {
 	c := &Check{
-		client: client,
-		url:    url,
+		client:      client,
+		url:         url,
+		method:      http.MethodGet,
+		maxBodySize: DefaultMaxBodySize,
+
+		followRedirects: true,
+		maxRedirects:    DefaultMaxRedirects,
 	}
 
 	for _, opt := range opts {
@@ -58,70 +134,219 @@ func NewCheck(client *http.Client, url string, opts ...Option) *Check {
 	return c
 }
 
+// Metrics returns the timings and metrics collected by the last run
+func (c *Check) Metrics() Metrics {
+	return c.metrics
+}
+
+// Response returns the response snapshot of the last run (nil if no response was received)
+func (c *Check) Response() *Response {
+	return c.response
+}
+
 // Run executes a check
-func (c *Check) Run() error {
-	req, err := http.NewRequest("GET", c.url, nil)
-	if err != nil {
-		return errors.Wrap(err, "Could not create request")
+func (c *Check) Run() *Result {
+	return c.RunContext(context.Background())
+}
+
+// RunContext executes a check. The check is aborted if ctx is canceled.
+func (c *Check) RunContext(ctx context.Context) *Result {
+	if c.tlsTarget != nil {
+		return c.runTLS(ctx)
 	}
 
-	req.SetBasicAuth(c.username, c.password)
-	req.Header.Set("User-Agent", "mauve/http-check")
+	c.metrics = Metrics{}
+	c.response = nil
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
 
-	resp, err := c.client.Do(req)
+	req, err := c.newRequest(ctx)
 	if err != nil {
-		if strings.Contains(err.Error(), "Timeout") {
-			return fmt.Errorf("Timeout exceeded (%v)", c.client.Timeout)
+		return critical(errors.Wrap(err, "Could not create request"))
+	}
+
+	var authHeaders []string
+	if c.auth != nil {
+		authHeaders, err = c.authenticate(req)
+		if err != nil {
+			return critical(errors.Wrap(err, "Authentication failed"))
 		}
+	}
 
-		return err
+	jar, err := c.jarForRun()
+	if err != nil {
+		return critical(errors.Wrap(err, "Could not create cookie jar"))
+	}
+	c.addCookies(req, jar)
+
+	if c.debug {
+		fmt.Fprintf(c.debugWriter, "Request: %s %s\n\n", req.Method, req.URL)
+	}
+
+	start := time.Now()
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), c.metrics.trace(start)))
+	defer func() {
+		c.metrics.Total = time.Since(start)
+	}()
+
+	redirects := []Redirect{}
+	cookies := []*http.Cookie{}
+	resp, err := c.clientWithRedirectPolicy(req.URL.Host, authHeaders, jar, &redirects, &cookies).Do(req)
+	if err != nil {
+		return c.requestError(ctx, err)
 	}
 	defer resp.Body.Close()
 
+	c.metrics.recordCertificate(resp.TLS, c.verifiedChains)
+
 	if c.debug {
 		fmt.Fprintln(c.debugWriter, "Status: "+resp.Status)
 		resp.Header.Write(c.debugWriter)
 		fmt.Fprintln(c.debugWriter, "")
+
+		if resp.TLS != nil {
+			writeTLSDetails(c.debugWriter, resp.TLS)
+		}
 	}
 
-	return c.validate(resp)
+	r, err := c.readResponse(resp)
+	c.metrics.Total = time.Since(start)
+	if err != nil {
+		return c.requestError(ctx, errors.Wrap(err, "Could not read body"))
+	}
+
+	r.Metrics = c.metrics
+	r.Redirects = redirects
+	r.Cookies = append(cookies, resp.Cookies()...)
+	c.response = r
+
+	res := newResult(c.validate(r))
+	if res.Status == OK {
+		res.Message = fmt.Sprintf("Request took %v", c.metrics.Total)
+	}
+
+	return res
 }
 
-// AssertStatusCodeIn tests if status code is in expected range
-func (c *Check) AssertStatusCodeIn(codes []uint32) {
-	c.assertions = append(c.assertions, func(resp *http.Response) error {
-		for _, c := range codes {
-			if uint32(resp.StatusCode) == c {
-				return nil
-			}
+func (c *Check) newRequest(ctx context.Context) (*http.Request, error) {
+	var body io.Reader
+	if c.requestBody != nil {
+		body = bytes.NewReader(c.requestBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, c.method, c.url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "mauve/http-check")
+
+	// headers of the check replace default headers, repeated headers are sent with all values
+	set := make(map[string]bool)
+	for _, h := range c.headers {
+		if strings.EqualFold(h.name, "Host") {
+			req.Host = h.value
+			continue
 		}
 
-		return fmt.Errorf("Unexpected status code: %s (expected: %v)", resp.Status, codes)
+		name := http.CanonicalHeaderKey(h.name)
+		if set[name] {
+			req.Header.Add(name, h.value)
+			continue
+		}
+
+		req.Header.Set(name, h.value)
+		set[name] = true
+	}
+
+	return req, nil
+}
+
+func (c *Check) requestError(ctx context.Context, err error) *Result {
+	if ctx.Err() == context.DeadlineExceeded {
+		return critical(fmt.Errorf("Timeout exceeded (%v)", c.timeout))
+	}
+
+	if strings.Contains(err.Error(), "Timeout") {
+		return critical(fmt.Errorf("Timeout exceeded (%v)", c.client.Timeout))
+	}
+
+	return critical(err)
+}
+
+// readResponse buffers the body (up to the maximum body size) and creates the response snapshot.
+// The remainder of a body exceeding the maximum body size is not read.
+func (c *Check) readResponse(resp *http.Response) (*Response, error) {
+	body := &countingReader{r: resp.Body}
+	b, err := ioutil.ReadAll(io.LimitReader(body, c.maxBodySize+1))
+	c.metrics.BodySize = body.n
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Response{
+		URL:           resp.Request.URL.String(),
+		StatusCode:    resp.StatusCode,
+		Status:        resp.Status,
+		Header:        resp.Header,
+		ContentLength: resp.ContentLength,
+		Body:          b,
+		TLS:           resp.TLS,
+	}
+
+	if int64(len(b)) > c.maxBodySize {
+		r.Body = b[:c.maxBodySize]
+		r.BodyTruncated = true
+
+		if resp.ContentLength > body.n {
+			c.metrics.BodySize = resp.ContentLength
+		}
+	}
+
+	return r, nil
+}
+
+func critical(err error) *Result {
+	return &Result{
+		Status:  Critical,
+		Message: err.Error(),
+	}
+}
+
+// AssertResponseTime tests the response time (in seconds) against warning and critical thresholds (nil for none)
+func (c *Check) AssertResponseTime(warning, critical *Range) {
+	t := thresholds{
+		warning:  warning,
+		critical: critical,
+	}
+
+	c.addAssertion(t.describe("Response time"), func(r *Response) error {
+		return t.evaluate(r.Metrics.Total.Seconds(), "Request took %vs")
 	})
 }
 
-// AssertHeaderExists tests if a specified header with specific value exists
-func (c *Check) AssertHeaderExists(name, value string) {
-	c.assertions = append(c.assertions, func(resp *http.Response) error {
-		h := resp.Header.Get(name)
-		if h != value {
-			return fmt.Errorf("Expected header '%s' with value '%v'", name, value)
+// AssertStatusCodeIn tests if status code is in expected range
+func (c *Check) AssertStatusCodeIn(codes []uint32) {
+	c.addAssertion(fmt.Sprintf("Status code in %v", codes), func(r *Response) error {
+		for _, c := range codes {
+			if uint32(r.StatusCode) == c {
+				return nil
+			}
 		}
 
-		return nil
+		return fmt.Errorf("Unexpected status code: %s (expected: %v)", r.Status, codes)
 	})
 }
 
 // AssertBodyContains tests if the body contains the specified string
 func (c *Check) AssertBodyContains(s string) {
-	c.assertions = append(c.assertions, func(resp *http.Response) error {
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return errors.Wrap(err, "Could not read body")
-		}
-
-		if !strings.Contains(string(b), s) {
-			return fmt.Errorf("String '%s' not found in body", s)
+	c.addAssertion(fmt.Sprintf("Body contains '%s'", s), func(r *Response) error {
+		if !strings.Contains(string(r.Body), s) {
+			return fmt.Errorf("String '%s' not found in %s", s, r.describeBody())
 		}
 
 		return nil
@@ -130,48 +355,33 @@ func (c *Check) AssertBodyContains(s string) {
 
 // AssertBodyMatches tests if the body matches the specified regex
 func (c *Check) AssertBodyMatches(regex string) {
-	c.assertions = append(c.assertions, func(resp *http.Response) error {
-		b, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return errors.Wrap(err, "Could not read body")
-		}
-
-		r, err := regexp.Compile(regex)
+	c.addAssertion(fmt.Sprintf("Body matches '%s'", regex), func(r *Response) error {
+		re, err := regexp.Compile(regex)
 		if err != nil {
 			return errors.Wrap(err, "Invalid regex")
 		}
 
-		if !r.Match(b) {
-			return fmt.Errorf("Regex '%s' does not match body", regex)
+		if !re.Match(r.Body) {
+			return fmt.Errorf("Regex '%s' does not match %s", regex, r.describeBody())
 		}
 
 		return nil
 	})
 }
 
-// AssertCertificateExpireDays tests the days until expiration of the returned certificate
-func (c *Check) AssertCertificateExpireDays(d time.Duration) {
-	c.assertions = append(c.assertions, func(resp *http.Response) error {
-		if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
-			return fmt.Errorf("No certificate returned")
-		}
-
-		first := resp.TLS.PeerCertificates[0]
-		min := time.Now().Add(d)
-		if !first.NotAfter.After(min) {
-			return fmt.Errorf("Certificate expires on %v", first.NotAfter)
-		}
-
-		return nil
+func (c *Check) addAssertion(name string, fn func(*Response) error) {
+	c.assertions = append(c.assertions, assertion{
+		name: name,
+		fn:   fn,
 	})
 }
 
-func (c *Check) validate(resp *http.Response) error {
-	for _, a := range c.assertions {
-		if err := a(resp); err != nil {
-			return err
-		}
+// validate evaluates all assertions against the response
+func (c *Check) validate(r *Response) []AssertionResult {
+	results := make([]AssertionResult, len(c.assertions))
+	for i, a := range c.assertions {
+		results[i] = newAssertionResult(a.name, a.fn(r))
 	}
 
-	return nil
+	return results
 }
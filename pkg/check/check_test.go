@@ -1,12 +1,15 @@
 package check
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,7 +30,64 @@ func TestValidReponse(t *testing.T) {
 	c.AssertStatusCodeIn([]uint32{200})
 	c.AssertBodyContains("valid")
 	c.AssertHeaderExists("X-Test2", "bar")
-	assert.Nil(t, c.Run())
+	assert.Equal(t, OK, c.Run().Status)
+}
+
+func TestMultipleBodyAssertions(t *testing.T) {
+	s := mockServer(200, "this is a valid response", http.Header{})
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL)
+	c.AssertBodyContains("valid")
+	c.AssertBodyMatches("^this is")
+	c.AssertBodyContains("response")
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+}
+
+func TestMaxBodySize(t *testing.T) {
+	s := mockServer(200, "this is a valid response", http.Header{})
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL, WithMaxBodySize(10))
+	c.AssertBodyContains("this is")
+	c.AssertBodyContains("response")
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "String 'response' not found in body (truncated to 10B)", res.Message)
+	assert.Equal(t, int64(24), c.Metrics().BodySize)
+}
+
+func TestMetrics(t *testing.T) {
+	s := mockServer(200, "this is a valid response", http.Header{})
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL)
+	c.AssertStatusCodeIn([]uint32{200})
+	assert.Equal(t, OK, c.Run().Status)
+
+	m := c.Metrics()
+	assert.Equal(t, int64(24), m.BodySize, "body size")
+	assert.True(t, m.Total > 0, "total")
+	assert.True(t, m.FirstByte > 0, "first byte")
+	assert.True(t, m.Total >= m.FirstByte, "total >= first byte")
+	assert.False(t, m.HasCertificate(), "certificate")
+	assert.Equal(t, int64(24), c.Response().ContentLength, "content length")
+}
+
+func TestMetricsWithCertificate(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(200)
+	}))
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL)
+	assert.Equal(t, OK, c.Run().Status)
+
+	m := c.Metrics()
+	assert.True(t, m.HasCertificate(), "certificate")
+	assert.Equal(t, s.Certificate().NotAfter, m.CertNotAfter)
+	assert.True(t, m.TLSHandshake > 0, "tls handshake")
 }
 
 func TestInvalidStausCode(t *testing.T) {
@@ -36,8 +96,9 @@ func TestInvalidStausCode(t *testing.T) {
 
 	c := NewCheck(s.Client(), s.URL)
 	c.AssertStatusCodeIn([]uint32{200})
-	err := c.Run()
-	assert.EqualError(t, err, "Unexpected status code: 404 Not Found (expected: [200])")
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Unexpected status code: 404 Not Found (expected: [200])", res.Message)
 }
 
 func TestTimeoutHandling(t *testing.T) {
@@ -52,8 +113,78 @@ func TestTimeoutHandling(t *testing.T) {
 	cl.Timeout = time.Millisecond * 1
 
 	c := NewCheck(cl, s.URL)
-	err := c.Run()
-	assert.EqualError(t, err, "Timeout exceeded (1ms)")
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Timeout exceeded (1ms)", res.Message)
+}
+
+func TestWithTimeout(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(200)
+		rw.(http.Flusher).Flush()
+
+		select {
+		case <-req.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL, WithTimeout(50*time.Millisecond))
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Timeout exceeded (50ms)", res.Message)
+}
+
+func TestRunContextCanceled(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	c := NewCheck(s.Client(), s.URL)
+	res := c.RunContext(ctx)
+	assert.Equal(t, Critical, res.Status)
+	assert.Contains(t, res.Message, "context canceled")
+}
+
+func TestBodyExceedingMaxBodySizeIsNotRead(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		b := make([]byte, 1024)
+		for {
+			_, err := rw.Write(b)
+			if err != nil {
+				return
+			}
+		}
+	}))
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL, WithMaxBodySize(10), WithTimeout(time.Second))
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+	assert.Equal(t, int64(11), c.Metrics().BodySize)
+	assert.True(t, c.Response().BodyTruncated)
+}
+
+func TestRequestHeadersReplaceDefaults(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-User-Agent", strings.Join(req.Header.Values("User-Agent"), ", "))
+		rw.Header().Set("X-Accept", strings.Join(req.Header.Values("Accept"), ", "))
+	}))
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL,
+		WithHeader("user-agent", "monitoring"),
+		WithHeader("Accept", "text/html"),
+		WithHeader("accept", "application/json"))
+	c.AssertHeaderExists("X-User-Agent", "monitoring")
+	c.AssertHeaderExists("X-Accept", "text/html, application/json")
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
 }
 
 func TestMissingHeader(t *testing.T) {
@@ -62,8 +193,9 @@ func TestMissingHeader(t *testing.T) {
 
 	c := NewCheck(s.Client(), s.URL)
 	c.AssertHeaderExists("X-Test", "Foo")
-	err := c.Run()
-	assert.EqualError(t, err, "Expected header 'X-Test' with value 'Foo'")
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Expected header 'X-Test' with value 'Foo'", res.Message)
 }
 
 func TestInvalidBody(t *testing.T) {
@@ -72,8 +204,9 @@ func TestInvalidBody(t *testing.T) {
 
 	c := NewCheck(s.Client(), s.URL)
 	c.AssertBodyContains("Mauve")
-	err := c.Run()
-	assert.EqualError(t, err, "String 'Mauve' not found in body")
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "String 'Mauve' not found in body", res.Message)
 }
 
 func TestInvalidBodyWithRegex(t *testing.T) {
@@ -82,8 +215,9 @@ func TestInvalidBodyWithRegex(t *testing.T) {
 
 	c := NewCheck(s.Client(), s.URL)
 	c.AssertBodyMatches("^\\d{5}$")
-	err := c.Run()
-	assert.EqualError(t, err, "Regex '^\\d{5}$' does not match body")
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Regex '^\\d{5}$' does not match body", res.Message)
 }
 
 func TestValidBodyWithRegex(t *testing.T) {
@@ -92,8 +226,8 @@ func TestValidBodyWithRegex(t *testing.T) {
 
 	c := NewCheck(s.Client(), s.URL)
 	c.AssertBodyMatches("^\\d{5}$")
-	err := c.Run()
-	assert.Nil(t, err)
+	res := c.Run()
+	assert.Equal(t, OK, res.Status)
 }
 
 func TestInvalidRegex(t *testing.T) {
@@ -102,14 +236,49 @@ func TestInvalidRegex(t *testing.T) {
 
 	c := NewCheck(s.Client(), s.URL)
 	c.AssertBodyMatches("[0-9]++")
-	err := c.Run()
-	assert.NotNil(t, err)
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
 }
 
 func TestWithBasicAuth(t *testing.T) {
 	c := NewCheck(http.DefaultClient, "www.mauve.de", WithBasicAuth("foo", "bar"))
-	assert.Equal(t, c.username, "foo", "username")
-	assert.Equal(t, c.password, "bar", "password")
+	assert.Equal(t, &basicAuth{username: "foo", password: "bar"}, c.auth)
+}
+
+func TestRequestMethodBodyAndHeaders(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		b, _ := ioutil.ReadAll(req.Body)
+
+		rw.Header().Set("X-Method", req.Method)
+		rw.Header().Set("X-Host", req.Host)
+		rw.Header().Set("X-Content-Type", req.Header.Get("Content-Type"))
+		rw.Header().Set("X-User-Agent", req.UserAgent())
+		rw.Header().Set("X-Authorization", req.Header.Get("Authorization"))
+		rw.WriteHeader(200)
+		rw.Write(b)
+	}))
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL,
+		WithMethod(http.MethodPost),
+		WithRequestBody([]byte(`{"foo":"bar"}`)),
+		WithHeader("Content-Type", "application/json"),
+		WithHeader("Host", "www.mauve.de"))
+	c.AssertHeaderExists("X-Method", "POST")
+	c.AssertHeaderExists("X-Host", "www.mauve.de")
+	c.AssertHeaderExists("X-Content-Type", "application/json")
+	c.AssertHeaderExists("X-User-Agent", "mauve/http-check")
+	c.AssertHeaderExists("X-Authorization", "")
+	c.AssertBodyContains(`{"foo":"bar"}`)
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+}
+
+func TestInvalidMethod(t *testing.T) {
+	c := NewCheck(http.DefaultClient, "http://www.mauve.de", WithMethod("GET /"))
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Contains(t, res.Message, "Could not create request")
 }
 
 func TestWithDebug(t *testing.T) {
@@ -123,8 +292,8 @@ func TestInvalidPath(t *testing.T) {
 
 	c := NewCheck(s.Client(), s.URL+"xxx")
 	c.AssertStatusCodeIn([]uint32{200})
-	err := c.Run()
-	assert.NotNil(t, err)
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
 }
 
 func TestInvalidUrl(t *testing.T) {
@@ -133,31 +302,31 @@ func TestInvalidUrl(t *testing.T) {
 
 	c := NewCheck(s.Client(), s.URL[1:])
 	c.AssertStatusCodeIn([]uint32{200})
-	err := c.Run()
-	assert.NotNil(t, err)
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
 }
 
 func TestAssertCertificateExpireDaysWithoutCert(t *testing.T) {
 	c := NewCheck(nil, "")
 	c.AssertStatusCodeIn([]uint32{200})
 
-	resp := &http.Response{
+	resp := &Response{
 		StatusCode: http.StatusOK,
 	}
 	resp.TLS = &tls.ConnectionState{}
 
 	c.AssertCertificateExpireDays(30 * 24 * time.Hour)
-	err := c.validate(resp)
+	res := newResult(c.validate(resp))
 
-	assert.NotNil(t, err)
-	assert.Equal(t, err.Error(), "No certificate returned")
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, res.Message, "No certificate returned")
 }
 
 func TestAssertCertificateExpireDaysWithSoonExpiringCert(t *testing.T) {
 	c := NewCheck(nil, "")
 	c.AssertStatusCodeIn([]uint32{200})
 
-	resp := &http.Response{
+	resp := &Response{
 		StatusCode: http.StatusOK,
 	}
 	notAfter := time.Now().Add(10 * time.Minute)
@@ -170,10 +339,121 @@ func TestAssertCertificateExpireDaysWithSoonExpiringCert(t *testing.T) {
 	}
 
 	c.AssertCertificateExpireDays(30 * 24 * time.Hour)
-	err := c.validate(resp)
+	res := newResult(c.validate(resp))
+
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, res.Message, fmt.Sprintf("Certificate expires on %v (0 days left)", notAfter))
+}
+
+func TestAssertCertificateExpireThresholds(t *testing.T) {
+	tests := []struct {
+		name     string
+		days     int
+		warning  string
+		critical string
+		expected Status
+	}{
+		{name: "ok", days: 60, warning: "30:", critical: "14:", expected: OK},
+		{name: "warning", days: 20, warning: "30:", critical: "14:", expected: Warning},
+		{name: "critical", days: 10, warning: "30:", critical: "14:", expected: Critical},
+		{name: "only warning", days: 10, warning: "30:", expected: Warning},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCheck(nil, "")
+			c.AssertCertificateExpireThresholds(mustParseRange(test.warning), mustParseRange(test.critical))
+
+			resp := &Response{
+				StatusCode: http.StatusOK,
+				TLS: &tls.ConnectionState{
+					PeerCertificates: []*x509.Certificate{
+						{
+							NotAfter: time.Now().Add(time.Duration(test.days)*24*time.Hour + time.Hour),
+						},
+					},
+				},
+			}
+
+			assert.Equal(t, test.expected, newResult(c.validate(resp)).Status)
+		})
+	}
+}
+
+func TestCriticalHasPrecedenceOverWarning(t *testing.T) {
+	c := NewCheck(nil, "")
+	c.AssertCertificateExpireThresholds(mustParseRange("30:"), nil)
+	c.AssertStatusCodeIn([]uint32{200})
+
+	resp := &Response{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 Not Found",
+		TLS: &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{
+				{
+					NotAfter: time.Now().Add(25 * time.Hour),
+				},
+			},
+		},
+	}
+
+	res := newResult(c.validate(resp))
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "2 of 2 assertions failed: Certificate expires in 1 days (warning threshold: 30:); Unexpected status code: 404 Not Found (expected: [200])", res.Message)
+}
+
+func TestAllAssertionsEvaluated(t *testing.T) {
+	s := mockServer(404, "the princess is in another castle", http.Header{
+		"X-Test": []string{"foo"},
+	})
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL)
+	c.AssertStatusCodeIn([]uint32{200})
+	c.AssertHeaderExists("X-Test", "foo")
+	c.AssertHeaderExists("X-Missing", "bar")
+	res := c.Run()
+
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "2 of 3 assertions failed: Unexpected status code: 404 Not Found (expected: [200]); Expected header 'X-Missing' with value 'bar'", res.Message)
+	assert.Equal(t, []AssertionResult{
+		{Name: "Status code in [200]", Status: Critical, Message: "Unexpected status code: 404 Not Found (expected: [200])"},
+		{Name: "Header 'X-Test' is 'foo'", Status: OK},
+		{Name: "Header 'X-Missing' is 'bar'", Status: Critical, Message: "Expected header 'X-Missing' with value 'bar'"},
+	}, res.Assertions)
+}
+
+func TestAssertResponseTime(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		rw.WriteHeader(200)
+	}))
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL)
+	c.AssertResponseTime(mustParseRange("0.01"), mustParseRange("10"))
+	res := c.Run()
+	assert.Equal(t, Warning, res.Status)
+	assert.Contains(t, res.Message, "(warning threshold: 0.01)")
+
+	c = NewCheck(s.Client(), s.URL)
+	c.AssertResponseTime(nil, mustParseRange("0.01"))
+	res = c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Contains(t, res.Message, "(critical threshold: 0.01)")
+}
+
+func mustParseRange(s string) *Range {
+	if len(s) == 0 {
+		return nil
+	}
+
+	r, err := ParseRange(s)
+	if err != nil {
+		panic(err)
+	}
 
-	assert.NotNil(t, err)
-	assert.Equal(t, err.Error(), fmt.Sprintf("Certificate expires on %v", notAfter))
+	return r
 }
 
 func mockServer(status int, body string, headers http.Header) *httptest.Server {
@@ -0,0 +1,163 @@
+package check
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+)
+
+// WithCookieJar enables a cookie jar for the check. Cookies set by responses (e.g. on redirects) are sent with
+// the following requests. Each run uses a new jar, so cookies are never shared between runs or checks.
+func WithCookieJar() Option {
+	return func(c *Check) {
+		c.cookieJar = true
+	}
+}
+
+// WithCookie adds a cookie to the request. If a cookie jar is used the cookie is added to the jar
+// for all paths of the host.
+func WithCookie(name, value string) Option {
+	return func(c *Check) {
+		c.cookies = append(c.cookies, &http.Cookie{Name: name, Value: value, Path: "/"})
+	}
+}
+
+// jarForRun returns the cookie jar used by the next run (nil if cookies are not stored)
+func (c *Check) jarForRun() (http.CookieJar, error) {
+	if c.jar != nil || !c.cookieJar {
+		return c.jar, nil
+	}
+
+	return cookiejar.New(nil)
+}
+
+// addCookies adds the cookies of the check to the jar or the request if no jar is used
+func (c *Check) addCookies(req *http.Request, jar http.CookieJar) {
+	if jar != nil {
+		if len(c.cookies) > 0 {
+			jar.SetCookies(req.URL, c.cookies)
+		}
+
+		return
+	}
+
+	for _, cookie := range c.cookies {
+		req.AddCookie(cookie)
+	}
+}
+
+// CookieExpectation defines a cookie expected to be set by the server and its attributes
+type CookieExpectation struct {
+	Name     string
+	Secure   bool
+	HTTPOnly bool
+
+	// SameSite is the expected SameSite attribute (not tested if 0)
+	SameSite http.SameSite
+
+	raw string
+}
+
+// ParseCookieExpectation parses an expected cookie in format 'name[;Secure][;HttpOnly][;SameSite=Strict|Lax|None]'
+func ParseCookieExpectation(s string) (*CookieExpectation, error) {
+	parts := strings.Split(s, ";")
+	e := &CookieExpectation{
+		Name: strings.TrimSpace(parts[0]),
+		raw:  s,
+	}
+
+	if len(e.Name) == 0 {
+		return nil, fmt.Errorf("Invalid cookie expectation '%s': name is missing", s)
+	}
+
+	for _, p := range parts[1:] {
+		attr, value := strings.TrimSpace(p), ""
+		if i := strings.Index(attr, "="); i >= 0 {
+			attr, value = strings.TrimSpace(attr[:i]), strings.TrimSpace(attr[i+1:])
+		}
+
+		switch {
+		case strings.EqualFold(attr, "Secure"):
+			e.Secure = true
+		case strings.EqualFold(attr, "HttpOnly"):
+			e.HTTPOnly = true
+		case strings.EqualFold(attr, "SameSite"):
+			ss, err := parseSameSite(value)
+			if err != nil {
+				return nil, err
+			}
+			e.SameSite = ss
+		default:
+			return nil, fmt.Errorf("Invalid cookie expectation '%s': unsupported attribute '%s' (expected Secure, HttpOnly or SameSite)", s, attr)
+		}
+	}
+
+	return e, nil
+}
+
+func parseSameSite(s string) (http.SameSite, error) {
+	switch strings.ToLower(s) {
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	default:
+		return 0, fmt.Errorf("Invalid SameSite value '%s' (expected Strict, Lax or None)", s)
+	}
+}
+
+func sameSiteName(s http.SameSite) string {
+	switch s {
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return "not set"
+	}
+}
+
+func (e *CookieExpectation) String() string {
+	return e.raw
+}
+
+// AssertCookie tests if the cookie was set by the server (by the final response or any redirect) with the expected
+// attributes. If the cookie was set multiple times, the last one is tested.
+func (c *Check) AssertCookie(e *CookieExpectation) {
+	c.addAssertion(fmt.Sprintf("Cookie '%s'", e), func(r *Response) error {
+		var cookie *http.Cookie
+		for _, ck := range r.Cookies {
+			if ck.Name == e.Name {
+				cookie = ck
+			}
+		}
+
+		if cookie == nil {
+			return fmt.Errorf("Cookie '%s' was not set", e.Name)
+		}
+
+		missing := []string{}
+		if e.Secure && !cookie.Secure {
+			missing = append(missing, "Secure")
+		}
+
+		if e.HTTPOnly && !cookie.HttpOnly {
+			missing = append(missing, "HttpOnly")
+		}
+
+		if e.SameSite != 0 && cookie.SameSite != e.SameSite {
+			missing = append(missing, fmt.Sprintf("SameSite=%s (is %s)", sameSiteName(e.SameSite), sameSiteName(cookie.SameSite)))
+		}
+
+		if len(missing) > 0 {
+			return fmt.Errorf("Cookie '%s' is missing attributes: %s", e.Name, strings.Join(missing, ", "))
+		}
+
+		return nil
+	})
+}
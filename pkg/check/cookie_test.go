@@ -0,0 +1,145 @@
+package check
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func consentServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("consent"); err != nil {
+			http.Redirect(w, r, "/consent", http.StatusFound)
+			return
+		}
+
+		fmt.Fprint(w, "content")
+	})
+	mux.HandleFunc("/consent/start", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/", http.StatusFound)
+	})
+	mux.HandleFunc("/consent", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "consent", Value: "1", Path: "/", Secure: true, HttpOnly: true, SameSite: http.SameSiteLaxMode})
+		http.Redirect(w, r, "/", http.StatusFound)
+	})
+
+	// TLS is used as secure cookies are only sent via https
+	return httptest.NewTLSServer(mux)
+}
+
+func TestCookieJar(t *testing.T) {
+	s := consentServer()
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL+"/", WithCookieJar())
+	c.AssertStatusCodeIn([]uint32{200})
+	c.AssertBodyContains("content")
+
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+	assert.Len(t, c.Response().Redirects, 2)
+
+	// cookies are not kept between runs
+	c.Run()
+	assert.Len(t, c.Response().Redirects, 2)
+}
+
+func TestWithoutCookieJar(t *testing.T) {
+	s := consentServer()
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL+"/", WithMaxRedirects(3))
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Contains(t, res.Message, "Stopped after 3 redirects")
+}
+
+func TestWithCookie(t *testing.T) {
+	s := consentServer()
+	defer s.Close()
+
+	for _, jar := range []bool{false, true} {
+		t.Run(fmt.Sprintf("jar %v", jar), func(t *testing.T) {
+			opts := []Option{WithCookie("consent", "1")}
+			if jar {
+				opts = append(opts, WithCookieJar())
+			}
+
+			c := NewCheck(s.Client(), s.URL+"/", opts...)
+			c.AssertBodyContains("content")
+
+			res := c.Run()
+			assert.Equal(t, OK, res.Status, res.Message)
+			assert.Empty(t, c.Response().Redirects)
+		})
+	}
+}
+
+func TestWithCookieRedirectToOtherPath(t *testing.T) {
+	s := consentServer()
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL+"/consent/start", WithCookie("consent", "1"), WithCookieJar())
+	c.AssertBodyContains("content")
+
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+	assert.Len(t, c.Response().Redirects, 1)
+}
+
+func TestParseCookieExpectation(t *testing.T) {
+	e, err := ParseCookieExpectation("session; Secure; httponly; SameSite=Strict")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "session", e.Name)
+		assert.True(t, e.Secure)
+		assert.True(t, e.HTTPOnly)
+		assert.Equal(t, http.SameSiteStrictMode, e.SameSite)
+	}
+
+	e, err = ParseCookieExpectation("consent")
+	if assert.NoError(t, err) {
+		assert.Equal(t, &CookieExpectation{Name: "consent", raw: "consent"}, e)
+	}
+
+	for _, s := range []string{"", ";Secure", "session;Path=/", "session;SameSite=Weak"} {
+		_, err := ParseCookieExpectation(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestAssertCookie(t *testing.T) {
+	s := consentServer()
+	defer s.Close()
+
+	tests := []struct {
+		expr     string
+		expected Status
+		message  string
+	}{
+		{expr: "consent;Secure;HttpOnly;SameSite=Lax", expected: OK},
+		{expr: "consent;SameSite=Strict", expected: Critical, message: "Cookie 'consent' is missing attributes: SameSite=Strict (is Lax)"},
+		{expr: "session", expected: Critical, message: "Cookie 'session' was not set"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			e, err := ParseCookieExpectation(test.expr)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			c := NewCheck(s.Client(), s.URL+"/", WithCookieJar())
+			c.AssertCookie(e)
+
+			res := c.Run()
+			assert.Equal(t, test.expected, res.Status, res.Message)
+			if len(test.message) > 0 {
+				assert.Equal(t, test.message, res.Message)
+			}
+		})
+	}
+}
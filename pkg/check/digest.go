@@ -0,0 +1,219 @@
+package check
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type digestAuth struct {
+	username string
+	password string
+}
+
+// DigestAuth authenticates requests using HTTP digest auth (RFC 7616, algorithms MD5 and SHA-256 with qop auth)
+func DigestAuth(username, password string) Authenticator {
+	return &digestAuth{username: username, password: password}
+}
+
+// Authenticate does nothing, the credentials are sent in response to the challenge of the server
+func (a *digestAuth) Authenticate(req *http.Request) error {
+	return nil
+}
+
+// RoundTripper sends the request again with credentials if the server responds with a digest challenge
+func (a *digestAuth) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+
+		ch := selectDigestChallenge(challenges(resp, "Digest"))
+		if ch == nil {
+			return resp, nil
+		}
+
+		r, err := resendRequest(req)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+
+		authz, err := a.authorization(ch, r)
+		if err != nil {
+			resp.Body.Close()
+			return nil, errors.Wrap(err, "Digest auth failed")
+		}
+		discardResponse(resp)
+
+		r.Header.Set("Authorization", authz)
+		return next.RoundTrip(r)
+	})
+}
+
+// digestChallenge contains the parameters of a digest challenge
+type digestChallenge map[string]string
+
+func (ch digestChallenge) algorithm() string {
+	if alg, found := ch["algorithm"]; found {
+		return strings.ToUpper(alg)
+	}
+
+	return "MD5"
+}
+
+// hasQopAuth returns if qop auth is offered by the server
+func (ch digestChallenge) hasQopAuth() bool {
+	for _, qop := range strings.Split(ch["qop"], ",") {
+		if strings.TrimSpace(qop) == "auth" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectDigestChallenge returns the supported challenge with the strongest algorithm (nil if none is supported)
+func selectDigestChallenge(challenges []string) digestChallenge {
+	var res digestChallenge
+	for _, s := range challenges {
+		ch := digestChallenge(parseAuthParams(s))
+		if len(ch["nonce"]) == 0 || (len(ch["qop"]) > 0 && !ch.hasQopAuth()) {
+			continue
+		}
+
+		switch ch.algorithm() {
+		case "SHA-256":
+			return ch
+		case "MD5":
+			if res == nil {
+				res = ch
+			}
+		}
+	}
+
+	return res
+}
+
+func (a *digestAuth) authorization(ch digestChallenge, req *http.Request) (string, error) {
+	newHash := md5.New
+	if ch.algorithm() == "SHA-256" {
+		newHash = sha256.New
+	}
+
+	h := func(s ...string) string {
+		return hashHex(newHash(), strings.Join(s, ":"))
+	}
+
+	uri := req.URL.RequestURI()
+	ha1 := h(a.username, ch["realm"], a.password)
+	ha2 := h(req.Method, uri)
+
+	params := []string{
+		fmt.Sprintf("username=%s", quote(a.username)),
+		fmt.Sprintf("realm=%s", quote(ch["realm"])),
+		fmt.Sprintf("nonce=%s", quote(ch["nonce"])),
+		fmt.Sprintf("uri=%s", quote(uri)),
+		fmt.Sprintf("algorithm=%s", ch.algorithm()),
+	}
+
+	if ch.hasQopAuth() {
+		cnonce, err := randomHex(16)
+		if err != nil {
+			return "", err
+		}
+
+		nc := "00000001"
+		params = append(params,
+			fmt.Sprintf("response=%s", quote(h(ha1, ch["nonce"], nc, cnonce, "auth", ha2))),
+			"qop=auth",
+			"nc="+nc,
+			fmt.Sprintf("cnonce=%s", quote(cnonce)))
+	} else {
+		params = append(params, fmt.Sprintf("response=%s", quote(h(ha1, ch["nonce"], ha2))))
+	}
+
+	if opaque, found := ch["opaque"]; found {
+		params = append(params, fmt.Sprintf("opaque=%s", quote(opaque)))
+	}
+
+	return "Digest " + strings.Join(params, ", "), nil
+}
+
+// parseAuthParams parses comma separated parameters of a challenge (e.g. realm="test", qop="auth,auth-int")
+func parseAuthParams(s string) map[string]string {
+	res := make(map[string]string)
+
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ,")
+		i := strings.Index(s, "=")
+		if i < 0 {
+			break
+		}
+
+		name := strings.ToLower(strings.TrimSpace(s[:i]))
+		s = strings.TrimLeft(s[i+1:], " ")
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			value, s = readQuoted(s[1:])
+		} else {
+			j := strings.Index(s, ",")
+			if j < 0 {
+				j = len(s)
+			}
+			value, s = strings.TrimSpace(s[:j]), s[j:]
+		}
+
+		res[name] = value
+	}
+
+	return res
+}
+
+// readQuoted reads a quoted string (opening quote already consumed) and returns the value and the remainder
+func readQuoted(s string) (string, string) {
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:]
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String(), ""
+}
+
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func hashHex(h hash.Hash, s string) string {
+	h.Write([]byte(s))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,128 @@
+package check
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// digestServer accepts requests authenticated with digest auth for user monitoring (password s3cr3t)
+func digestServer(t *testing.T, challenges ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authz := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authz, "Digest ") {
+			for _, ch := range challenges {
+				w.Header().Add("WWW-Authenticate", ch)
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		p := parseAuthParams(strings.TrimPrefix(authz, "Digest "))
+		var h func() hash.Hash = md5.New
+		if p["algorithm"] == "SHA-256" {
+			h = sha256.New
+		}
+
+		ha1 := hashHex(h(), "monitoring:"+p["realm"]+":s3cr3t")
+		ha2 := hashHex(h(), r.Method+":"+r.URL.RequestURI())
+		expected := hashHex(h(), ha1+":"+p["nonce"]+":"+ha2)
+		if p["qop"] == "auth" {
+			expected = hashHex(h(), strings.Join([]string{ha1, p["nonce"], p["nc"], p["cnonce"], "auth", ha2}, ":"))
+		}
+
+		if p["username"] != "monitoring" || p["response"] != expected || p["uri"] != r.URL.RequestURI() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s|%s|%s", p["algorithm"], p["opaque"], body)
+	}))
+}
+
+func TestDigestAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		challenges []string
+		expected   string
+	}{
+		{
+			name:       "SHA-256",
+			challenges: []string{`Digest realm="test", qop="auth", algorithm=SHA-256, nonce="abc", opaque="xyz"`},
+			expected:   "SHA-256|xyz|",
+		},
+		{
+			name:       "MD5",
+			challenges: []string{`Digest realm="test", qop="auth,auth-int", nonce="abc"`},
+			expected:   "MD5||",
+		},
+		{
+			name: "prefer SHA-256",
+			challenges: []string{
+				`Basic realm="test"`,
+				`Digest realm="test", qop="auth", algorithm=MD5, nonce="abc"`,
+				`Digest realm="test", qop="auth", algorithm=SHA-256, nonce="abc"`,
+			},
+			expected: "SHA-256||",
+		},
+		{
+			name:       "without qop",
+			challenges: []string{`Digest realm="test", nonce="abc"`},
+			expected:   "MD5||",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := digestServer(t, test.challenges...)
+			defer s.Close()
+
+			c := NewCheck(s.Client(), s.URL+"/status?full=1", WithAuthenticator(DigestAuth("monitoring", "s3cr3t")))
+			c.AssertStatusCodeIn([]uint32{200})
+			c.AssertBodyContains(test.expected)
+			res := c.Run()
+			assert.Equal(t, OK, res.Status, res.Message)
+		})
+	}
+}
+
+func TestDigestAuthRequestBody(t *testing.T) {
+	s := digestServer(t, `Digest realm="test", qop="auth", nonce="abc"`)
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL, WithMethod(http.MethodPost), WithRequestBody([]byte("payload")),
+		WithAuthenticator(DigestAuth("monitoring", "s3cr3t")))
+	c.AssertBodyContains("|payload")
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+}
+
+func TestDigestAuthInvalidCredentials(t *testing.T) {
+	s := digestServer(t, `Digest realm="test", qop="auth", nonce="abc"`)
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL, WithAuthenticator(DigestAuth("monitoring", "wrong")))
+	c.AssertStatusCodeIn([]uint32{200})
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, 401, c.Response().StatusCode)
+}
+
+func TestParseAuthParams(t *testing.T) {
+	p := parseAuthParams(`realm="a \"b\", c", qop="auth,auth-int", algorithm=SHA-256,nonce=abc`)
+	assert.Equal(t, map[string]string{
+		"realm":     `a "b", c`,
+		"qop":       "auth,auth-int",
+		"algorithm": "SHA-256",
+		"nonce":     "abc",
+	}, p)
+}
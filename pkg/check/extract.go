@@ -0,0 +1,113 @@
+package check
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// variableRegex matches placeholders in format {{name}}
+var variableRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_\-]*)\s*\}\}`)
+
+// Extractor extracts a value from a response (e.g. a CSRF or auth token) to use it in the following steps of a scenario
+type Extractor struct {
+	name        string
+	description string
+	fn          func(*Response) (string, error)
+}
+
+// ExtractHeader extracts the value of a response header
+func ExtractHeader(name, header string) *Extractor {
+	return &Extractor{
+		name:        name,
+		description: fmt.Sprintf("header %s", header),
+		fn: func(r *Response) (string, error) {
+			v := r.Header.Get(header)
+			if len(v) == 0 {
+				return "", fmt.Errorf("Header %s not found", header)
+			}
+
+			return v, nil
+		},
+	}
+}
+
+// ExtractRegex extracts the first capture group (or the whole match if the expression has no group) of a regular expression matching the body
+func ExtractRegex(name, expr string) (*Extractor, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Extractor{
+		name:        name,
+		description: fmt.Sprintf("regex %s", expr),
+		fn: func(r *Response) (string, error) {
+			m := re.FindSubmatch(r.Body)
+			if m == nil {
+				return "", fmt.Errorf("Regex '%s' does not match %s", expr, r.describeBody())
+			}
+
+			if len(m) > 1 {
+				return string(m[1]), nil
+			}
+
+			return string(m[0]), nil
+		},
+	}, nil
+}
+
+// ExtractJSONPath extracts a value from a JSON body
+func ExtractJSONPath(name, expr string) *Extractor {
+	return &Extractor{
+		name:        name,
+		description: fmt.Sprintf("JSON %s", expr),
+		fn: func(r *Response) (string, error) {
+			v, err := r.jsonPath(expr)
+			if err != nil {
+				return "", err
+			}
+
+			return formatJSONValue(v), nil
+		},
+	}
+}
+
+// Name returns the name of the variable the value is assigned to
+func (e *Extractor) Name() string {
+	return e.name
+}
+
+// extractTo adds an assertion to the check assigning the extracted value to the variable (fails if no value could be extracted)
+func (c *Check) extractTo(e *Extractor, vars map[string]string) {
+	c.addAssertion(fmt.Sprintf("Extract %s from %s", e.name, e.description), func(r *Response) error {
+		v, err := e.fn(r)
+		if err != nil {
+			return err
+		}
+
+		vars[e.name] = v
+		return nil
+	})
+}
+
+// Substitute replaces placeholders in format {{name}} with the values of the variables
+func Substitute(s string, vars map[string]string) (string, error) {
+	missing := []string{}
+
+	res := variableRegex.ReplaceAllStringFunc(s, func(m string) string {
+		name := variableRegex.FindStringSubmatch(m)[1]
+		if v, found := vars[name]; found {
+			return v
+		}
+
+		missing = append(missing, name)
+		return m
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("Undefined variables: %s", strings.Join(missing, ", "))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,80 @@
+package check
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AssertHeaderExists tests if a specified header with specific value exists
+func (c *Check) AssertHeaderExists(name, value string) {
+	c.addAssertion(fmt.Sprintf("Header '%s' is '%s'", name, value), func(r *Response) error {
+		h := r.Header.Get(name)
+		if h != value {
+			return fmt.Errorf("Expected header '%s' with value '%v'", name, value)
+		}
+
+		return nil
+	})
+}
+
+// AssertHeaderPresent tests if a header is present (regardless of its value)
+func (c *Check) AssertHeaderPresent(name string) {
+	c.addAssertion(fmt.Sprintf("Header '%s' is present", name), func(r *Response) error {
+		if len(r.Header.Values(name)) == 0 {
+			return fmt.Errorf("Header '%s' is missing", name)
+		}
+
+		return nil
+	})
+}
+
+// AssertHeaderAbsent tests if a header is not present
+func (c *Check) AssertHeaderAbsent(name string) {
+	c.addAssertion(fmt.Sprintf("Header '%s' is absent", name), func(r *Response) error {
+		if values := r.Header.Values(name); len(values) > 0 {
+			return fmt.Errorf("Header '%s' is present with value '%s' (expected to be absent)", name, strings.Join(values, ", "))
+		}
+
+		return nil
+	})
+}
+
+// AssertHeaderContains tests if a value of the header contains the specified string
+func (c *Check) AssertHeaderContains(name, s string) {
+	c.addAssertion(fmt.Sprintf("Header '%s' contains '%s'", name, s), func(r *Response) error {
+		return matchHeader(r, name, func(v string) bool {
+			return strings.Contains(v, s)
+		}, fmt.Sprintf("does not contain '%s'", s))
+	})
+}
+
+// AssertHeaderMatches tests if a value of the header matches the specified regex
+func (c *Check) AssertHeaderMatches(name, regex string) {
+	c.addAssertion(fmt.Sprintf("Header '%s' matches '%s'", name, regex), func(r *Response) error {
+		re, err := regexp.Compile(regex)
+		if err != nil {
+			return errors.Wrap(err, "Invalid regex")
+		}
+
+		return matchHeader(r, name, re.MatchString, fmt.Sprintf("does not match '%s'", regex))
+	})
+}
+
+// matchHeader returns nil if any value of the header satisfies the match function
+func matchHeader(r *Response, name string, match func(string) bool, failure string) error {
+	values := r.Header.Values(name)
+	if len(values) == 0 {
+		return fmt.Errorf("Header '%s' is missing", name)
+	}
+
+	for _, v := range values {
+		if match(v) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Header '%s' with value '%s' %s", name, strings.Join(values, ", "), failure)
+}
@@ -0,0 +1,87 @@
+package check
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderAssertions(t *testing.T) {
+	header := http.Header{
+		"Strict-Transport-Security": []string{"max-age=31536000; includeSubDomains"},
+		"Server":                    []string{"nginx"},
+		"Set-Cookie":                []string{"a=1", "session=abc; Secure"},
+	}
+
+	tests := []struct {
+		name     string
+		assert   func(c *Check)
+		expected Status
+		message  string
+	}{
+		{
+			name:     "present",
+			assert:   func(c *Check) { c.AssertHeaderPresent("Strict-Transport-Security") },
+			expected: OK,
+		},
+		{
+			name:     "missing",
+			assert:   func(c *Check) { c.AssertHeaderPresent("Content-Security-Policy") },
+			expected: Critical,
+			message:  "Header 'Content-Security-Policy' is missing",
+		},
+		{
+			name:     "absent",
+			assert:   func(c *Check) { c.AssertHeaderAbsent("X-Powered-By") },
+			expected: OK,
+		},
+		{
+			name:     "not absent",
+			assert:   func(c *Check) { c.AssertHeaderAbsent("Server") },
+			expected: Critical,
+			message:  "Header 'Server' is present with value 'nginx' (expected to be absent)",
+		},
+		{
+			name:     "contains",
+			assert:   func(c *Check) { c.AssertHeaderContains("Strict-Transport-Security", "includeSubDomains") },
+			expected: OK,
+		},
+		{
+			name:     "contains in any value",
+			assert:   func(c *Check) { c.AssertHeaderContains("Set-Cookie", "Secure") },
+			expected: OK,
+		},
+		{
+			name:     "does not contain",
+			assert:   func(c *Check) { c.AssertHeaderContains("Server", "apache") },
+			expected: Critical,
+			message:  "Header 'Server' with value 'nginx' does not contain 'apache'",
+		},
+		{
+			name:     "matches",
+			assert:   func(c *Check) { c.AssertHeaderMatches("Strict-Transport-Security", `max-age=\d{8,}`) },
+			expected: OK,
+		},
+		{
+			name:     "does not match",
+			assert:   func(c *Check) { c.AssertHeaderMatches("Strict-Transport-Security", `max-age=\d{9,}`) },
+			expected: Critical,
+			message:  `Header 'Strict-Transport-Security' with value 'max-age=31536000; includeSubDomains' does not match 'max-age=\d{9,}'`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCheck(nil, "")
+			test.assert(c)
+
+			res := newResult(c.validate(&Response{Header: header}))
+			assert.Equal(t, test.expected, res.Status, res.Message)
+
+			if len(test.message) > 0 {
+				assert.Equal(t, test.message, res.Message)
+			}
+		})
+	}
+}
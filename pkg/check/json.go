@@ -0,0 +1,142 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/pkg/errors"
+)
+
+// AssertJSONPath tests if the value selected by the JSONPath expression equals the expected value.
+// Numbers, booleans and null are compared by their JSON representation, objects and arrays by their JSON encoding.
+func (c *Check) AssertJSONPath(expr, expected string) {
+	c.addAssertion(fmt.Sprintf("JSON %s == '%s'", expr, expected), func(r *Response) error {
+		v, err := r.jsonPath(expr)
+		if err != nil {
+			return err
+		}
+
+		actual := formatJSONValue(v)
+		if actual != expected {
+			return fmt.Errorf("JSON %s is '%s' (expected: '%s')", expr, actual, expected)
+		}
+
+		return nil
+	})
+}
+
+// AssertJSONPathExists tests if the JSONPath expression selects a value
+func (c *Check) AssertJSONPathExists(expr string) {
+	c.addAssertion(fmt.Sprintf("JSON %s exists", expr), func(r *Response) error {
+		_, err := r.jsonPath(expr)
+		return err
+	})
+}
+
+// AssertJSONPathCompare tests the numeric value selected by the JSONPath expression against the expected value
+func (c *Check) AssertJSONPathCompare(expr string, op Operator, expected float64) {
+	c.addAssertion(fmt.Sprintf("JSON %s %s %v", expr, op, expected), func(r *Response) error {
+		v, err := r.jsonPath(expr)
+		if err != nil {
+			return err
+		}
+
+		actual, ok := toFloat(v)
+		if !ok {
+			return fmt.Errorf("JSON %s is '%s' (expected a number %s %v)", expr, formatJSONValue(v), op, expected)
+		}
+
+		if !op.Compare(actual, expected) {
+			return fmt.Errorf("JSON %s is %v (expected: %s %v)", expr, actual, op, expected)
+		}
+
+		return nil
+	})
+}
+
+// AssertJSONPathLength tests the length of the array (or object) selected by the JSONPath expression
+func (c *Check) AssertJSONPathLength(expr string, op Operator, expected int) {
+	c.addAssertion(fmt.Sprintf("JSON length(%s) %s %d", expr, op, expected), func(r *Response) error {
+		v, err := r.jsonPath(expr)
+		if err != nil {
+			return err
+		}
+
+		var l int
+		switch x := v.(type) {
+		case []interface{}:
+			l = len(x)
+		case map[string]interface{}:
+			l = len(x)
+		default:
+			return fmt.Errorf("JSON %s is '%s' (expected an array)", expr, formatJSONValue(v))
+		}
+
+		if !op.Compare(float64(l), float64(expected)) {
+			return fmt.Errorf("JSON length(%s) is %d (expected: %s %d)", expr, l, op, expected)
+		}
+
+		return nil
+	})
+}
+
+func (r *Response) jsonPath(expr string) (interface{}, error) {
+	doc, err := r.JSON()
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := jsonpath.Get(expr, doc)
+	if err != nil {
+		return nil, fmt.Errorf("JSON %s not found: %v", expr, err)
+	}
+
+	return v, nil
+}
+
+// JSON returns the decoded JSON body. The body is decoded only once.
+func (r *Response) JSON() (interface{}, error) {
+	if !r.jsonDecoded {
+		r.jsonDecoded = true
+		r.jsonErr = json.Unmarshal(r.Body, &r.json)
+		if r.jsonErr != nil {
+			r.jsonErr = errors.Wrap(r.jsonErr, "Could not parse body as JSON")
+		}
+	}
+
+	return r.json, r.jsonErr
+}
+
+func formatJSONValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	default:
+		b, err := json.Marshal(x)
+		if err != nil {
+			return fmt.Sprint(x)
+		}
+
+		return string(b)
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case string:
+		f, err := strconv.ParseFloat(x, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,112 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const jsonBody = `{"status":"UP","db":{"ok":true,"latency":12.5},"nodes":[{"name":"a"},{"name":"b"}],"version":3,"maintenance":null}`
+
+func TestAssertJSONPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		assert   func(c *Check)
+		expected Status
+		message  string
+	}{
+		{
+			name:     "string equals",
+			assert:   func(c *Check) { c.AssertJSONPath("$.status", "UP") },
+			expected: OK,
+		},
+		{
+			name:     "string differs",
+			assert:   func(c *Check) { c.AssertJSONPath("$.status", "DOWN") },
+			expected: Critical,
+			message:  "JSON $.status is 'UP' (expected: 'DOWN')",
+		},
+		{
+			name:     "bool equals",
+			assert:   func(c *Check) { c.AssertJSONPath("$.db.ok", "true") },
+			expected: OK,
+		},
+		{
+			name:     "number equals",
+			assert:   func(c *Check) { c.AssertJSONPath("$.version", "3") },
+			expected: OK,
+		},
+		{
+			name:     "null equals",
+			assert:   func(c *Check) { c.AssertJSONPath("$.maintenance", "null") },
+			expected: OK,
+		},
+		{
+			name:     "array index",
+			assert:   func(c *Check) { c.AssertJSONPath("$.nodes[1].name", "b") },
+			expected: OK,
+		},
+		{
+			name:     "exists",
+			assert:   func(c *Check) { c.AssertJSONPathExists("$.db.ok") },
+			expected: OK,
+		},
+		{
+			name:     "does not exist",
+			assert:   func(c *Check) { c.AssertJSONPathExists("$.db.missing") },
+			expected: Critical,
+		},
+		{
+			name:     "compare",
+			assert:   func(c *Check) { c.AssertJSONPathCompare("$.db.latency", Less, 100) },
+			expected: OK,
+		},
+		{
+			name:     "compare fails",
+			assert:   func(c *Check) { c.AssertJSONPathCompare("$.db.latency", Less, 10) },
+			expected: Critical,
+			message:  "JSON $.db.latency is 12.5 (expected: < 10)",
+		},
+		{
+			name:     "compare non numeric",
+			assert:   func(c *Check) { c.AssertJSONPathCompare("$.db.ok", Equal, 1) },
+			expected: Critical,
+			message:  "JSON $.db.ok is 'true' (expected a number == 1)",
+		},
+		{
+			name:     "length",
+			assert:   func(c *Check) { c.AssertJSONPathLength("$.nodes", GreaterOrEqual, 2) },
+			expected: OK,
+		},
+		{
+			name:     "length fails",
+			assert:   func(c *Check) { c.AssertJSONPathLength("$.nodes", Equal, 3) },
+			expected: Critical,
+			message:  "JSON length($.nodes) is 2 (expected: == 3)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCheck(nil, "")
+			test.assert(c)
+
+			res := newResult(c.validate(&Response{Body: []byte(jsonBody)}))
+			assert.Equal(t, test.expected, res.Status, res.Message)
+
+			if len(test.message) > 0 {
+				assert.Equal(t, test.message, res.Message)
+			}
+		})
+	}
+}
+
+func TestAssertJSONPathInvalidBody(t *testing.T) {
+	c := NewCheck(nil, "")
+	c.AssertJSONPathExists("$.status")
+	c.AssertJSONPath("$.status", "UP")
+
+	res := newResult(c.validate(&Response{Body: []byte("<html></html>")}))
+	assert.Equal(t, Critical, res.Status)
+	assert.Contains(t, res.Assertions[0].Message, "Could not parse body as JSON")
+}
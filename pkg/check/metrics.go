@@ -0,0 +1,87 @@
+package check
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http/httptrace"
+	"time"
+)
+
+// Metrics contains timings and sizes measured while performing a check
+type Metrics struct {
+	DNSLookup    time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration
+	Total        time.Duration
+
+	// BodySize is the number of body bytes received. If the body exceeds the maximum body size it is not read
+	// completely, the size announced by Content-Length is used instead (if present).
+	BodySize int64
+
+	// CertNotAfter is the expiration date of the certificate expiring first in the chain returned by the server
+	// (and the verified chains if enabled). It is zero if no certificate was returned.
+	CertNotAfter time.Time
+}
+
+// CertExpireDays returns the number of days until the first certificate of the chain expires
+func (m *Metrics) CertExpireDays() float64 {
+	return time.Until(m.CertNotAfter).Hours() / 24
+}
+
+// HasCertificate returns true if the server returned a certificate
+func (m *Metrics) HasCertificate() bool {
+	return !m.CertNotAfter.IsZero()
+}
+
+func (m *Metrics) trace(start time.Time) *httptrace.ClientTrace {
+	var dnsStart, connectStart, tlsStart time.Time
+
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			m.DNSLookup = time.Since(dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			connectStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			m.Connect = time.Since(connectStart)
+		},
+		TLSHandshakeStart: func() {
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			m.TLSHandshake = time.Since(tlsStart)
+		},
+		GotFirstResponseByte: func() {
+			m.FirstByte = time.Since(start)
+		},
+	}
+}
+
+// recordCertificate records the expiration of the certificate evaluated by the certificate expiration assertions
+func (m *Metrics) recordCertificate(state *tls.ConnectionState, verifiedChains bool) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return
+	}
+
+	m.CertNotAfter = firstExpiringInState(state, verifiedChains).NotAfter
+}
+
+type countingReader struct {
+	r io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *countingReader) Close() error {
+	return r.r.Close()
+}
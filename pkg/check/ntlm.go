@@ -0,0 +1,108 @@
+package check
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+
+	"github.com/Azure/go-ntlmssp"
+	"github.com/pkg/errors"
+)
+
+type ntlmAuth struct {
+	username string
+	password string
+}
+
+// NTLMAuth authenticates requests using NTLM (v2). The domain can be specified as part of the username (DOMAIN\user).
+func NTLMAuth(username, password string) Authenticator {
+	return &ntlmAuth{username: username, password: password}
+}
+
+// Authenticate does nothing, the credentials are sent in response to the challenge of the server
+func (a *ntlmAuth) Authenticate(req *http.Request) error {
+	return nil
+}
+
+// RoundTripper performs the NTLM handshake for each request.
+// NTLM authenticates the connection, so a dedicated transport limited to one connection per host is used for the check run.
+// This way the handshake is not interleaved with requests of other checks sharing the transport.
+func (a *ntlmAuth) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	var t *http.Transport
+	if tr, ok := next.(*http.Transport); ok {
+		t = tr.Clone()
+		t.MaxConnsPerHost = 1
+		next = t
+	}
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := a.roundTrip(next, req)
+		if err != nil || t == nil {
+			return resp, err
+		}
+
+		resp.Body = &closeIdleConnsBody{ReadCloser: resp.Body, t: t}
+		return resp, nil
+	})
+}
+
+func (a *ntlmAuth) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	user, domain, domainNeeded := ntlmssp.GetDomain(a.username)
+
+	negotiate, err := ntlmssp.NewNegotiateMessage(domain, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "NTLM auth failed")
+	}
+
+	r, err := resendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Authorization", "NTLM "+base64.StdEncoding.EncodeToString(negotiate))
+
+	resp, err := next.RoundTrip(r)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	var challenge []byte
+	for _, ch := range challenges(resp, "NTLM") {
+		b, err := base64.StdEncoding.DecodeString(ch)
+		if err == nil && len(b) > 0 {
+			challenge = b
+			break
+		}
+	}
+
+	if len(challenge) == 0 {
+		// NTLM not offered or credentials rejected
+		return resp, nil
+	}
+	discardResponse(resp)
+
+	authenticate, err := ntlmssp.ProcessChallenge(challenge, user, a.password, domainNeeded)
+	if err != nil {
+		return nil, errors.Wrap(err, "NTLM auth failed")
+	}
+
+	r, err = resendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Authorization", "NTLM "+base64.StdEncoding.EncodeToString(authenticate))
+
+	return next.RoundTrip(r)
+}
+
+// closeIdleConnsBody closes the connections of the dedicated transport after the response was read
+type closeIdleConnsBody struct {
+	io.ReadCloser
+	t *http.Transport
+}
+
+func (b *closeIdleConnsBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.t.CloseIdleConnections()
+
+	return err
+}
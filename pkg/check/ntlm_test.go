@@ -0,0 +1,117 @@
+package check
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ntlmChallengeMessage returns a minimal NTLM challenge message (type 2)
+func ntlmChallengeMessage() []byte {
+	b := &bytes.Buffer{}
+	b.WriteString("NTLMSSP\x00")
+	binary.Write(b, binary.LittleEndian, uint32(2))
+	binary.Write(b, binary.LittleEndian, []uint16{0, 0})
+	binary.Write(b, binary.LittleEndian, uint32(48))
+	binary.Write(b, binary.LittleEndian, uint32(0x201)) // unicode, NTLM
+	b.Write([]byte("12345678"))
+	b.Write(make([]byte, 8))
+	binary.Write(b, binary.LittleEndian, []uint16{0, 0})
+	binary.Write(b, binary.LittleEndian, uint32(48))
+
+	return b.Bytes()
+}
+
+func utf16LE(s string) []byte {
+	b := &bytes.Buffer{}
+	binary.Write(b, binary.LittleEndian, utf16.Encode([]rune(s)))
+	return b.Bytes()
+}
+
+// ntlmServer performs the NTLM handshake and verifies it is done on a single connection
+func ntlmServer(t *testing.T) *httptest.Server {
+	challengedConn := ""
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(r.Header.Get("Authorization"), "NTLM "))
+		if len(msg) < 12 || !bytes.HasPrefix(msg, []byte("NTLMSSP\x00")) {
+			w.Header().Set("WWW-Authenticate", "NTLM")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch msg[8] {
+		case 1:
+			challengedConn = r.RemoteAddr
+			w.Header().Set("WWW-Authenticate", "NTLM "+base64.StdEncoding.EncodeToString(ntlmChallengeMessage()))
+			w.WriteHeader(http.StatusUnauthorized)
+		case 3:
+			if r.RemoteAddr != challengedConn || !bytes.Contains(msg, utf16LE("monitoring")) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write(append([]byte("authenticated|"), body...))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestNTLMAuth(t *testing.T) {
+	s := ntlmServer(t)
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL, WithMethod(http.MethodPost), WithRequestBody([]byte("payload")),
+		WithAuthenticator(NTLMAuth(`MAUVE\monitoring`, "s3cr3t")))
+	c.AssertStatusCodeIn([]uint32{200})
+	c.AssertBodyContains("authenticated|payload")
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+}
+
+func TestNTLMAuthInvalidChallenge(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the decodable prefix is a truncated challenge message
+		challenge := base64.StdEncoding.EncodeToString(ntlmChallengeMessage())
+		w.Header().Set("WWW-Authenticate", "NTLM "+challenge[:16]+"!"+challenge[16:])
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL, WithAuthenticator(NTLMAuth("monitoring", "s3cr3t")))
+	c.AssertStatusCodeIn([]uint32{200})
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Contains(t, res.Message, "Unexpected status code: 401")
+}
+
+func TestNTLMAuthNotOffered(t *testing.T) {
+	s := echoAuthServer()
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL, WithAuthenticator(NTLMAuth("monitoring", "s3cr3t")))
+	c.AssertStatusCodeIn([]uint32{200})
+	c.AssertBodyContains("NTLM ")
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+}
+
+func TestPasswordAuth(t *testing.T) {
+	for _, scheme := range []string{"", "basic", "Digest", "ntlm"} {
+		_, err := PasswordAuth(scheme, "monitoring", "s3cr3t")
+		assert.NoError(t, err, scheme)
+	}
+
+	_, err := PasswordAuth("kerberos", "monitoring", "s3cr3t")
+	assert.Error(t, err)
+}
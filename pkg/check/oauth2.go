@@ -0,0 +1,134 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// tokenExpiryDelta is subtracted from the lifetime of a token to renew it before it expires
+const tokenExpiryDelta = 10 * time.Second
+
+// OAuth2ClientCredentials authenticates requests with an access token retrieved from an OAuth2 token endpoint
+// using the client credentials grant (RFC 6749, section 4.4). Tokens are cached until they expire, so an instance
+// should be shared by all checks using the same client.
+type OAuth2ClientCredentials struct {
+	client       *http.Client
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewOAuth2ClientCredentials creates a new OAuth2 client. The client is used to request tokens from the token endpoint.
+func NewOAuth2ClientCredentials(client *http.Client, tokenURL, clientID, clientSecret string, scopes []string) *OAuth2ClientCredentials {
+	return &OAuth2ClientCredentials{
+		client:       client,
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+	}
+}
+
+// Authenticate sends the access token in the Authorization header
+func (o *OAuth2ClientCredentials) Authenticate(req *http.Request) error {
+	token, err := o.Token(req)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token returns the cached access token or requests a new one if it is expired.
+// The context of req is used for the token request.
+func (o *OAuth2ClientCredentials) Token(req *http.Request) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.token) > 0 && time.Now().Before(o.expiry) {
+		return o.token, nil
+	}
+
+	token, expiresIn, err := o.requestToken(req)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not get OAuth2 token")
+	}
+
+	// tokens without lifetime are not cached
+	o.token = ""
+	if expiresIn > 0 {
+		o.token = token
+		o.expiry = time.Now().Add(expiresIn - tokenExpiryDelta)
+	}
+
+	return token, nil
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (o *OAuth2ClientCredentials) requestToken(req *http.Request) (string, time.Duration, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(o.scopes) > 0 {
+		form.Set("scope", strings.Join(o.scopes, " "))
+	}
+
+	tr, err := http.NewRequestWithContext(req.Context(), http.MethodPost, o.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+
+	tr.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	tr.Header.Set("User-Agent", "mauve/http-check")
+	tr.SetBasicAuth(url.QueryEscape(o.clientID), url.QueryEscape(o.clientSecret))
+
+	resp, err := o.client.Do(tr)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("Token endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+
+	t := &tokenResponse{}
+	err = json.Unmarshal(b, t)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "Could not parse token response")
+	}
+
+	if len(t.AccessToken) == 0 {
+		return "", 0, fmt.Errorf("Token response contains no access token")
+	}
+
+	if len(t.TokenType) > 0 && !strings.EqualFold(t.TokenType, "bearer") {
+		return "", 0, fmt.Errorf("Unsupported token type '%s'", t.TokenType)
+	}
+
+	return t.AccessToken, time.Duration(t.ExpiresIn) * time.Second, nil
+}
@@ -0,0 +1,56 @@
+package check
+
+import "fmt"
+
+// Operator is a comparison operator used by numeric assertions
+type Operator string
+
+const (
+	// Equal tests if a value is equal to the expected value
+	Equal Operator = "=="
+
+	// NotEqual tests if a value differs from the expected value
+	NotEqual Operator = "!="
+
+	// Less tests if a value is less than the expected value
+	Less Operator = "<"
+
+	// LessOrEqual tests if a value is less than or equal to the expected value
+	LessOrEqual Operator = "<="
+
+	// Greater tests if a value is greater than the expected value
+	Greater Operator = ">"
+
+	// GreaterOrEqual tests if a value is greater than or equal to the expected value
+	GreaterOrEqual Operator = ">="
+)
+
+// ParseOperator parses a comparison operator
+func ParseOperator(s string) (Operator, error) {
+	switch op := Operator(s); op {
+	case Equal, NotEqual, Less, LessOrEqual, Greater, GreaterOrEqual:
+		return op, nil
+	default:
+		return "", fmt.Errorf("Invalid operator '%s'", s)
+	}
+}
+
+// Compare applies the operator to the values a and b
+func (op Operator) Compare(a, b float64) bool {
+	switch op {
+	case Equal:
+		return a == b
+	case NotEqual:
+		return a != b
+	case Less:
+		return a < b
+	case LessOrEqual:
+		return a <= b
+	case Greater:
+		return a > b
+	case GreaterOrEqual:
+		return a >= b
+	default:
+		return false
+	}
+}
@@ -0,0 +1,108 @@
+package check
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	spkiPinPrefix        = "sha256/"
+	fingerprintPinPrefix = "sha256:"
+)
+
+// Pin identifies a certificate either by the SHA-256 hash of its public key (SPKI) or by its SHA-256 fingerprint
+type Pin struct {
+	raw    string
+	spki   bool
+	digest []byte
+}
+
+// ParsePin parses a certificate pin.
+// SPKI pins are specified as 'sha256/<base64>' (as used by HPKP and curl), fingerprints as 'sha256:<hex>'
+// (colons between bytes are optional)
+func ParsePin(s string) (*Pin, error) {
+	if strings.HasPrefix(s, spkiPinPrefix) {
+		digest, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, spkiPinPrefix))
+		if err != nil || len(digest) != sha256.Size {
+			return nil, fmt.Errorf("Invalid SPKI pin '%s' (expected base64 encoded SHA-256 hash)", s)
+		}
+
+		return &Pin{raw: s, spki: true, digest: digest}, nil
+	}
+
+	if strings.HasPrefix(s, fingerprintPinPrefix) {
+		digest, err := hex.DecodeString(strings.ReplaceAll(strings.TrimPrefix(s, fingerprintPinPrefix), ":", ""))
+		if err != nil || len(digest) != sha256.Size {
+			return nil, fmt.Errorf("Invalid fingerprint pin '%s' (expected hex encoded SHA-256 hash)", s)
+		}
+
+		return &Pin{raw: s, digest: digest}, nil
+	}
+
+	return nil, fmt.Errorf("Invalid pin '%s' (expected 'sha256/<base64>' or 'sha256:<hex>')", s)
+}
+
+func (p *Pin) String() string {
+	return p.raw
+}
+
+// Matches tests if the pin identifies the certificate
+func (p *Pin) Matches(cert *x509.Certificate) bool {
+	var digest [sha256.Size]byte
+	if p.spki {
+		digest = sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	} else {
+		digest = sha256.Sum256(cert.Raw)
+	}
+
+	return bytes.Equal(p.digest, digest[:])
+}
+
+// SPKIPin returns the SPKI pin of a certificate
+func SPKIPin(cert *x509.Certificate) string {
+	digest := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return spkiPinPrefix + base64.StdEncoding.EncodeToString(digest[:])
+}
+
+// AssertCertificatePin tests if at least one certificate of the verified chains matches one of the pins.
+// Pinning the certificate of an intermediate or root CA allows certificate renewals without changing the pin.
+// Without verification (insecure) only the leaf certificate is matched, since any other certificate sent by the
+// server is not proven to belong to the chain.
+func (c *Check) AssertCertificatePin(pins []*Pin) {
+	c.addAssertion(fmt.Sprintf("Certificate pinned to %v", pins), func(r *Response) error {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return fmt.Errorf("No certificate returned")
+		}
+
+		for _, cert := range pinnableCertificates(r.TLS) {
+			for _, p := range pins {
+				if p.Matches(cert) {
+					return nil
+				}
+			}
+		}
+
+		return fmt.Errorf("No certificate matches the pins (certificate %s: %s)",
+			r.TLS.PeerCertificates[0].Subject.CommonName, SPKIPin(r.TLS.PeerCertificates[0]))
+	})
+}
+
+// pinnableCertificates returns the certificates of the verified chains or the leaf certificate if the chain was not verified
+func pinnableCertificates(state *tls.ConnectionState) []*x509.Certificate {
+	if len(state.VerifiedChains) == 0 {
+		return state.PeerCertificates[:1]
+	}
+
+	var res []*x509.Certificate
+	for _, chain := range state.VerifiedChains {
+		res = append(res, chain...)
+	}
+
+	return res
+}
@@ -0,0 +1,138 @@
+package check
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePin(t *testing.T) {
+	tests := []struct {
+		pin   string
+		valid bool
+	}{
+		{pin: "sha256/47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", valid: true},
+		{pin: "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", valid: true},
+		{pin: "sha256:E3:B0:C4:42:98:FC:1C:14:9A:FB:F4:C8:99:6F:B9:24:27:AE:41:E4:64:9B:93:4C:A4:95:99:1B:78:52:B8:55", valid: true},
+		{pin: "sha256/abc", valid: false},
+		{pin: "sha256:e3b0", valid: false},
+		{pin: "sha256:xyz", valid: false},
+		{pin: "md5:e3b0c44298fc1c149afbf4c8996fb924", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pin, func(t *testing.T) {
+			_, err := ParsePin(test.pin)
+			assert.Equal(t, test.valid, err == nil, err)
+		})
+	}
+}
+
+func fingerprintPin(cert *x509.Certificate) string {
+	digest := sha256.Sum256(cert.Raw)
+	return fingerprintPinPrefix + strings.ToUpper(hex.EncodeToString(digest[:]))
+}
+
+func TestAssertCertificatePin(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer s.Close()
+
+	other := &x509.Certificate{Raw: []byte("other"), RawSubjectPublicKeyInfo: []byte("other")}
+
+	tests := []struct {
+		name     string
+		pins     []string
+		expected Status
+	}{
+		{
+			name:     "SPKI pin",
+			pins:     []string{SPKIPin(s.Certificate())},
+			expected: OK,
+		},
+		{
+			name:     "fingerprint pin",
+			pins:     []string{fingerprintPin(s.Certificate())},
+			expected: OK,
+		},
+		{
+			name:     "one of multiple pins",
+			pins:     []string{SPKIPin(other), SPKIPin(s.Certificate())},
+			expected: OK,
+		},
+		{
+			name:     "no matching pin",
+			pins:     []string{SPKIPin(other), fingerprintPin(other)},
+			expected: Critical,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pins := make([]*Pin, len(test.pins))
+			for i, p := range test.pins {
+				var err error
+				pins[i], err = ParsePin(p)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c := NewCheck(s.Client(), s.URL)
+			c.AssertCertificatePin(pins)
+
+			res := c.Run()
+			assert.Equal(t, test.expected, res.Status, res.Message)
+		})
+	}
+}
+
+func TestAssertCertificatePinWithoutCert(t *testing.T) {
+	pin, _ := ParsePin("sha256/47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+
+	c := NewCheck(nil, "")
+	c.AssertCertificatePin([]*Pin{pin})
+
+	res := newResult(c.validate(&Response{TLS: &tls.ConnectionState{}}))
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "No certificate returned", res.Message)
+}
+
+func TestAssertCertificatePinChain(t *testing.T) {
+	leaf := &x509.Certificate{Raw: []byte("leaf"), RawSubjectPublicKeyInfo: []byte("leaf")}
+	ca := &x509.Certificate{Raw: []byte("ca"), RawSubjectPublicKeyInfo: []byte("ca")}
+	pin, _ := ParsePin(SPKIPin(ca))
+
+	tests := []struct {
+		name     string
+		state    *tls.ConnectionState
+		expected Status
+	}{
+		{
+			name:     "verified chain",
+			state:    &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}, VerifiedChains: [][]*x509.Certificate{{leaf, ca}}},
+			expected: OK,
+		},
+		{
+			name:     "unverified extra certificate",
+			state:    &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf, ca}},
+			expected: Critical,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCheck(nil, "")
+			c.AssertCertificatePin([]*Pin{pin})
+
+			res := newResult(c.validate(&Response{TLS: test.state}))
+			assert.Equal(t, test.expected, res.Status, res.Message)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package check
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Range is a threshold range in nagios format (e.g. `10`, `10:`, `~:10`, `10:20`, `@10:20`)
+// see https://nagios-plugins.org/doc/guidelines.html#THRESHOLDFORMAT
+type Range struct {
+	start  float64
+	end    float64
+	inside bool
+	raw    string
+}
+
+// ParseRange parses a threshold range in nagios format
+func ParseRange(s string) (*Range, error) {
+	r := &Range{
+		start: 0,
+		end:   math.Inf(1),
+		raw:   s,
+	}
+
+	v := strings.TrimSpace(s)
+	if strings.HasPrefix(v, "@") {
+		r.inside = true
+		v = v[1:]
+	}
+
+	if len(v) == 0 {
+		return nil, fmt.Errorf("Invalid range '%s'", s)
+	}
+
+	start, end := "", v
+	if i := strings.Index(v, ":"); i >= 0 {
+		start, end = v[:i], v[i+1:]
+	}
+
+	var err error
+	if start == "~" {
+		r.start = math.Inf(-1)
+	} else if len(start) > 0 {
+		r.start, err = strconv.ParseFloat(start, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid range '%s': start is not a number", s)
+		}
+	}
+
+	if len(end) > 0 {
+		r.end, err = strconv.ParseFloat(end, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid range '%s': end is not a number", s)
+		}
+	}
+
+	if r.start > r.end {
+		return nil, fmt.Errorf("Invalid range '%s': start is greater than end", s)
+	}
+
+	return r, nil
+}
+
+// Alert returns true if the value v should raise an alert
+func (r *Range) Alert(v float64) bool {
+	in := v >= r.start && v <= r.end
+	if r.inside {
+		return in
+	}
+
+	return !in
+}
+
+func (r *Range) String() string {
+	return r.raw
+}
+
+type thresholds struct {
+	warning  *Range
+	critical *Range
+}
+
+// describe returns a description of the thresholds for the value name
+func (t thresholds) describe(name string) string {
+	limits := []string{}
+	if t.warning != nil {
+		limits = append(limits, "warning: "+t.warning.String())
+	}
+
+	if t.critical != nil {
+		limits = append(limits, "critical: "+t.critical.String())
+	}
+
+	return fmt.Sprintf("%s (%s)", name, strings.Join(limits, ", "))
+}
+
+// evaluate tests the value v against the thresholds. format is used to describe the value in the error message.
+func (t thresholds) evaluate(v float64, format string) error {
+	if t.critical != nil && t.critical.Alert(v) {
+		return &failure{
+			status: Critical,
+			msg:    fmt.Sprintf(format+" (critical threshold: %s)", v, t.critical),
+		}
+	}
+
+	if t.warning != nil && t.warning.Alert(v) {
+		return &failure{
+			status: Warning,
+			msg:    fmt.Sprintf(format+" (warning threshold: %s)", v, t.warning),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		alerts []float64
+		passes []float64
+	}{
+		{name: "upper bound", raw: "10", alerts: []float64{-1, 10.5, 11}, passes: []float64{0, 5, 10}},
+		{name: "lower bound", raw: "10:", alerts: []float64{-1, 9.9}, passes: []float64{10, 1000}},
+		{name: "negative infinity", raw: "~:10", alerts: []float64{10.1}, passes: []float64{-100, 0, 10}},
+		{name: "between", raw: "10:20", alerts: []float64{9, 21}, passes: []float64{10, 15, 20}},
+		{name: "inside", raw: "@10:20", alerts: []float64{10, 15, 20}, passes: []float64{9, 21}},
+		{name: "inside with upper bound", raw: "@5", alerts: []float64{0, 5}, passes: []float64{-1, 6}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := ParseRange(test.raw)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			for _, v := range test.alerts {
+				assert.True(t, r.Alert(v), "%v should alert", v)
+			}
+
+			for _, v := range test.passes {
+				assert.False(t, r.Alert(v), "%v should not alert", v)
+			}
+
+			assert.Equal(t, test.raw, r.String())
+		})
+	}
+}
+
+func TestParseRangeInvalid(t *testing.T) {
+	for _, raw := range []string{"", "@", "abc", "10:abc", "20:10", "~"} {
+		_, err := ParseRange(raw)
+		assert.NotNil(t, err, raw)
+	}
+}
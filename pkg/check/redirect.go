@@ -0,0 +1,159 @@
+package check
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxRedirects is the maximum number of redirects followed if not specified otherwise
+const DefaultMaxRedirects = 10
+
+// Redirect is a redirect response received while performing a check
+type Redirect struct {
+	URL        string
+	StatusCode int
+	Location   string
+}
+
+func (r Redirect) String() string {
+	return fmt.Sprintf("%d %s -> %s", r.StatusCode, r.URL, r.Location)
+}
+
+// WithoutRedirects disables following redirects. The redirect response is validated instead.
+func WithoutRedirects() Option {
+	return func(c *Check) {
+		c.followRedirects = false
+	}
+}
+
+// WithMaxRedirects defines the maximum number of redirects to follow
+func WithMaxRedirects(n int) Option {
+	return func(c *Check) {
+		c.followRedirects = true
+		c.maxRedirects = n
+	}
+}
+
+// WithoutRedirectsToOtherHosts fails the check if a redirect leads to another host
+// (e.g. because the client certificate must not be presented to other hosts)
+func WithoutRedirectsToOtherHosts() Option {
+	return func(c *Check) {
+		c.sameHostRedirects = true
+	}
+}
+
+// clientWithRedirectPolicy returns a copy of the client using the cookie jar and the transport of the authenticator,
+// applying the redirect policy of the check and recording all redirects and cookies received.
+// Credentials are only sent to the host of the check, not to redirect targets on other hosts.
+func (c *Check) clientWithRedirectPolicy(host string, authHeaders []string, jar http.CookieJar, redirects *[]Redirect, cookies *[]*http.Cookie) *http.Client {
+	cl := *c.client
+	cl.Jar = jar
+
+	if a, ok := c.auth.(RoundTripAuthenticator); ok {
+		next := cl.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+
+		authenticated := a.RoundTripper(next)
+		cl.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host != host {
+				return next.RoundTrip(req)
+			}
+
+			return authenticated.RoundTrip(req)
+		})
+	}
+
+	cl.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		// the redirect response is the final response if the redirect is not followed
+		if !c.followRedirects {
+			return http.ErrUseLastResponse
+		}
+
+		if len(via) > c.maxRedirects {
+			return fmt.Errorf("Stopped after %d redirects", c.maxRedirects)
+		}
+
+		if req.URL.Host != host && c.sameHostRedirects {
+			return fmt.Errorf("Redirect to other host %s not followed", req.URL.Host)
+		}
+
+		if req.URL.Host != host {
+			for _, name := range authHeaders {
+				req.Header.Del(name)
+			}
+		}
+
+		prev := via[len(via)-1]
+		r := Redirect{
+			URL:      prev.URL.String(),
+			Location: req.URL.String(),
+		}
+
+		if req.Response != nil {
+			r.StatusCode = req.Response.StatusCode
+			*cookies = append(*cookies, req.Response.Cookies()...)
+		}
+
+		*redirects = append(*redirects, r)
+
+		if c.debug {
+			fmt.Fprintf(c.debugWriter, "Redirect: %s\n", r)
+		}
+
+		return nil
+	}
+
+	return &cl
+}
+
+// AssertFinalURL tests if the URL of the last request (after following all redirects) equals the expected URL
+func (c *Check) AssertFinalURL(expected string) {
+	c.addAssertion(fmt.Sprintf("Final URL is '%s'", expected), func(r *Response) error {
+		if r.URL != expected {
+			return fmt.Errorf("Final URL is '%s' (expected: '%s')", r.URL, expected)
+		}
+
+		return nil
+	})
+}
+
+// AssertLocation tests if the Location header of the response equals the expected value
+func (c *Check) AssertLocation(expected string) {
+	c.addAssertion(fmt.Sprintf("Location is '%s'", expected), func(r *Response) error {
+		actual := r.Header.Get("Location")
+		if len(actual) == 0 {
+			return fmt.Errorf("No Location header returned (status: %s)", r.Status)
+		}
+
+		if actual != expected {
+			return fmt.Errorf("Location is '%s' (expected: '%s')", actual, expected)
+		}
+
+		return nil
+	})
+}
+
+// AssertRedirectChain tests if the URLs requested (starting with the URL of the check and ending with the final URL)
+// equal the expected URLs
+func (c *Check) AssertRedirectChain(expected []string) {
+	c.addAssertion(fmt.Sprintf("Redirect chain is %s", strings.Join(expected, " -> ")), func(r *Response) error {
+		actual := r.redirectChain()
+		if strings.Join(actual, " ") != strings.Join(expected, " ") {
+			return fmt.Errorf("Redirect chain is %s (expected: %s)", strings.Join(actual, " -> "), strings.Join(expected, " -> "))
+		}
+
+		return nil
+	})
+}
+
+func (r *Response) redirectChain() []string {
+	chain := make([]string, 0, len(r.Redirects)+1)
+	for _, rd := range r.Redirects {
+		chain = append(chain, rd.URL)
+	}
+
+	return append(chain, r.URL)
+}
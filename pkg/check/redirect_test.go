@@ -0,0 +1,91 @@
+package check
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func redirectServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(rw http.ResponseWriter, req *http.Request) {
+		http.Redirect(rw, req, "/b", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/b", func(rw http.ResponseWriter, req *http.Request) {
+		http.Redirect(rw, req, "/c", http.StatusFound)
+	})
+	mux.HandleFunc("/c", func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestFollowRedirects(t *testing.T) {
+	s := redirectServer()
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL+"/a")
+	c.AssertStatusCodeIn([]uint32{200})
+	c.AssertFinalURL(s.URL + "/c")
+	c.AssertRedirectChain([]string{s.URL + "/a", s.URL + "/b", s.URL + "/c"})
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+
+	assert.Equal(t, []Redirect{
+		{URL: s.URL + "/a", StatusCode: 301, Location: s.URL + "/b"},
+		{URL: s.URL + "/b", StatusCode: 302, Location: s.URL + "/c"},
+	}, c.Response().Redirects)
+}
+
+func TestRedirectChainMismatch(t *testing.T) {
+	s := redirectServer()
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL+"/b")
+	c.AssertRedirectChain([]string{s.URL + "/a", s.URL + "/c"})
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Redirect chain is "+s.URL+"/b -> "+s.URL+"/c (expected: "+s.URL+"/a -> "+s.URL+"/c)", res.Message)
+}
+
+func TestWithoutRedirects(t *testing.T) {
+	s := redirectServer()
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL+"/a", WithoutRedirects())
+	c.AssertStatusCodeIn([]uint32{301})
+	c.AssertLocation("/b")
+	c.AssertFinalURL(s.URL + "/a")
+	c.AssertRedirectChain([]string{s.URL + "/a"})
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+	assert.Empty(t, c.Response().Redirects)
+}
+
+func TestLocationMismatch(t *testing.T) {
+	s := redirectServer()
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL+"/a", WithoutRedirects())
+	c.AssertLocation("https://www.mauve.de/")
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Location is '/b' (expected: 'https://www.mauve.de/')", res.Message)
+}
+
+func TestMaxRedirects(t *testing.T) {
+	s := redirectServer()
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL+"/a", WithMaxRedirects(1))
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Contains(t, res.Message, "Stopped after 1 redirects")
+
+	c = NewCheck(s.Client(), s.URL+"/a", WithMaxRedirects(2))
+	res = c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+}
@@ -0,0 +1,84 @@
+package check
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/antchfx/xmlquery"
+	"golang.org/x/net/html"
+)
+
+// DefaultMaxBodySize is the maximum number of body bytes buffered for assertions if not specified otherwise
+const DefaultMaxBodySize = 4 << 20
+
+// Response is a snapshot of the response which is passed to all assertions
+type Response struct {
+	// URL is the URL of the last request (after following redirects)
+	URL string
+
+	// Redirects contains all redirects received before the final response
+	Redirects []Redirect
+
+	StatusCode int
+	Status     string
+	Header     http.Header
+
+	// ContentLength is the length announced in the Content-Length header (-1 if unknown)
+	ContentLength int64
+
+	// Cookies contains the cookies set by all responses (including redirects) in the order received
+	Cookies []*http.Cookie
+
+	// Body contains the response body (limited to the maximum body size of the check)
+	Body []byte
+
+	// BodyTruncated is true if the response body exceeded the maximum body size
+	BodyTruncated bool
+
+	// ServerName is the name sent via SNI (TLS checks only)
+	ServerName string
+
+	TLS     *tls.ConnectionState
+	Metrics Metrics
+
+	json        interface{}
+	jsonErr     error
+	jsonDecoded bool
+	html        *html.Node
+	xml         *xmlquery.Node
+}
+
+func (r *Response) describeBody() string {
+	if r.BodyTruncated {
+		return "body (truncated to " + formatBytes(len(r.Body)) + ")"
+	}
+
+	return "body"
+}
+
+// hostname returns the hostname of the URL of the last request (or the SNI name of TLS checks)
+func (r *Response) hostname() (string, error) {
+	if len(r.ServerName) > 0 {
+		return r.ServerName, nil
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return "", err
+	}
+
+	return u.Hostname(), nil
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("%dMiB", n>>20)
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return fmt.Sprintf("%dKiB", n>>10)
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
@@ -0,0 +1,146 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+
+	"github.com/pkg/errors"
+)
+
+// Step is a check in a scenario
+type Step struct {
+	// Name identifies the step in the result
+	Name string
+
+	// NewCheck creates the check of the step. vars contains the values extracted by the previous steps.
+	NewCheck func(vars map[string]string) (*Check, error)
+
+	// Extract defines values to extract from the response for the following steps
+	Extract []*Extractor
+}
+
+// StepResult is the outcome of a step
+type StepResult struct {
+	Name     string
+	Result   *Result
+	Metrics  Metrics
+	Response *Response
+}
+
+// Scenario runs checks in sequence. All requests share a cookie jar, values extracted from a response
+// can be used in the requests of the following steps. The scenario stops at the first failing step.
+type Scenario struct {
+	steps   []*Step
+	results []StepResult
+	metrics Metrics
+}
+
+// NewScenario creates a new scenario
+func NewScenario(steps ...*Step) *Scenario {
+	return &Scenario{
+		steps: steps,
+	}
+}
+
+// Steps returns the results of the steps performed by the last run
+func (s *Scenario) Steps() []StepResult {
+	return s.results
+}
+
+// Metrics returns the sum of the metrics of all steps performed by the last run.
+// The certificate expiration is the earliest expiration of all steps.
+func (s *Scenario) Metrics() Metrics {
+	return s.metrics
+}
+
+// Run executes all steps
+func (s *Scenario) Run() *Result {
+	return s.RunContext(context.Background())
+}
+
+// RunContext executes all steps. The running step is aborted if ctx is canceled.
+func (s *Scenario) RunContext(ctx context.Context) *Result {
+	s.results = nil
+	s.metrics = Metrics{}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return critical(errors.Wrap(err, "Could not create cookie jar"))
+	}
+
+	vars := make(map[string]string)
+	assertions := make([]AssertionResult, 0, len(s.steps))
+
+	for _, step := range s.steps {
+		res := s.runStep(ctx, step, jar, vars)
+		assertions = append(assertions, AssertionResult{
+			Name:    fmt.Sprintf("Step '%s'", step.Name),
+			Status:  res.Status,
+			Message: res.Message,
+		})
+
+		if res.Status >= Critical {
+			return &Result{
+				Status:     res.Status,
+				Message:    fmt.Sprintf("Step '%s' failed: %s", step.Name, res.Message),
+				Assertions: assertions,
+			}
+		}
+	}
+
+	res := newResult(assertions)
+	if res.Status == OK {
+		res.Message = fmt.Sprintf("%d steps took %v", len(s.steps), s.metrics.Total)
+	}
+
+	return res
+}
+
+func (s *Scenario) runStep(ctx context.Context, step *Step, jar http.CookieJar, vars map[string]string) *Result {
+	c, err := step.NewCheck(vars)
+	if err != nil {
+		res := &Result{
+			Status:  Unknown,
+			Message: err.Error(),
+		}
+		s.results = append(s.results, StepResult{Name: step.Name, Result: res})
+
+		return res
+	}
+
+	c.useCookieJar(jar)
+	for _, e := range step.Extract {
+		c.extractTo(e, vars)
+	}
+
+	res := c.RunContext(ctx)
+	s.results = append(s.results, StepResult{
+		Name:     step.Name,
+		Result:   res,
+		Metrics:  c.Metrics(),
+		Response: c.Response(),
+	})
+	s.metrics.add(c.Metrics())
+
+	return res
+}
+
+// useCookieJar sends and stores cookies in jar instead of a jar per run (HTTP checks only)
+func (c *Check) useCookieJar(jar http.CookieJar) {
+	c.jar = jar
+}
+
+func (m *Metrics) add(o Metrics) {
+	m.DNSLookup += o.DNSLookup
+	m.Connect += o.Connect
+	m.TLSHandshake += o.TLSHandshake
+	m.FirstByte += o.FirstByte
+	m.Total += o.Total
+	m.BodySize += o.BodySize
+
+	if o.HasCertificate() && (!m.HasCertificate() || o.CertNotAfter.Before(m.CertNotAfter)) {
+		m.CertNotAfter = o.CertNotAfter
+	}
+}
@@ -0,0 +1,174 @@
+package check
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loginServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `<form><input type="hidden" name="csrf" value="t0k3n"></form>`)
+			return
+		}
+
+		b, _ := ioutil.ReadAll(r.Body)
+		if string(b) != "csrf=t0k3n" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1"})
+		w.Header().Set("X-Request-Id", "42")
+		fmt.Fprint(w, `{"access_token": "secret"}`)
+	})
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "s1" || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		fmt.Fprint(w, "Welcome")
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func loginScenario(s *httptest.Server, accountPath string) *Scenario {
+	csrf, _ := ExtractRegex("csrf", `name="csrf" value="([^"]+)"`)
+
+	return NewScenario(
+		&Step{
+			Name: "form",
+			NewCheck: func(vars map[string]string) (*Check, error) {
+				c := NewCheck(s.Client(), s.URL+"/login")
+				c.AssertStatusCodeIn([]uint32{200})
+				return c, nil
+			},
+			Extract: []*Extractor{csrf},
+		},
+		&Step{
+			Name: "login",
+			NewCheck: func(vars map[string]string) (*Check, error) {
+				body, err := Substitute("csrf={{csrf}}", vars)
+				if err != nil {
+					return nil, err
+				}
+
+				c := NewCheck(s.Client(), s.URL+"/login", WithMethod(http.MethodPost), WithRequestBody([]byte(body)))
+				c.AssertStatusCodeIn([]uint32{200})
+				return c, nil
+			},
+			Extract: []*Extractor{
+				ExtractJSONPath("token", "$.access_token"),
+				ExtractHeader("request_id", "X-Request-Id"),
+			},
+		},
+		&Step{
+			Name: "account",
+			NewCheck: func(vars map[string]string) (*Check, error) {
+				auth, err := Substitute("Bearer {{token}}", vars)
+				if err != nil {
+					return nil, err
+				}
+
+				c := NewCheck(s.Client(), s.URL+accountPath, WithHeader("Authorization", auth))
+				c.AssertStatusCodeIn([]uint32{200})
+				c.AssertBodyContains("Welcome")
+				return c, nil
+			},
+		},
+	)
+}
+
+func TestScenario(t *testing.T) {
+	s := loginServer()
+	defer s.Close()
+
+	sc := loginScenario(s, "/account")
+	res := sc.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+	assert.Len(t, res.Assertions, 3)
+	assert.Equal(t, "Step 'login'", res.Assertions[1].Name)
+
+	steps := sc.Steps()
+	if assert.Len(t, steps, 3) {
+		assert.Equal(t, "login", steps[1].Name)
+		assert.Equal(t, 200, steps[1].Response.StatusCode)
+		assert.Len(t, steps[1].Result.Assertions, 3)
+	}
+
+	assert.Equal(t, steps[0].Metrics.Total+steps[1].Metrics.Total+steps[2].Metrics.Total, sc.Metrics().Total)
+}
+
+func TestScenarioStopsAtFailingStep(t *testing.T) {
+	s := loginServer()
+	defer s.Close()
+
+	sc := loginScenario(s, "/missing")
+	sc.steps = append(sc.steps, &Step{
+		Name: "never",
+		NewCheck: func(vars map[string]string) (*Check, error) {
+			t.Fatal("step after failing step must not run")
+			return nil, nil
+		},
+	})
+
+	res := sc.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Contains(t, res.Message, "Step 'account' failed: 2 of 2 assertions failed: Unexpected status code: 404")
+	assert.Len(t, sc.Steps(), 3)
+}
+
+func TestScenarioExtractionFailure(t *testing.T) {
+	s := mockServer(200, "no token here", http.Header{})
+	defer s.Close()
+
+	sc := NewScenario(&Step{
+		Name: "form",
+		NewCheck: func(vars map[string]string) (*Check, error) {
+			return NewCheck(s.Client(), s.URL), nil
+		},
+		Extract: []*Extractor{ExtractJSONPath("token", "$.token")},
+	})
+
+	res := sc.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Contains(t, res.Message, "Step 'form' failed:")
+}
+
+func TestScenarioInvalidStep(t *testing.T) {
+	sc := NewScenario(&Step{
+		Name: "broken",
+		NewCheck: func(vars map[string]string) (*Check, error) {
+			_, err := Substitute("{{missing}}", vars)
+			return nil, err
+		},
+	})
+
+	res := sc.Run()
+	assert.Equal(t, Unknown, res.Status)
+	assert.Equal(t, "Step 'broken' failed: Undefined variables: missing", res.Message)
+}
+
+func TestSubstitute(t *testing.T) {
+	vars := map[string]string{"token": "abc", "id": "42"}
+
+	s, err := Substitute("/orders/{{id}}?token={{ token }}", vars)
+	assert.NoError(t, err)
+	assert.Equal(t, "/orders/42?token=abc", s)
+
+	s, err = Substitute("${HOME} {not} {{", vars)
+	assert.NoError(t, err)
+	assert.Equal(t, "${HOME} {not} {{", s)
+
+	_, err = Substitute("{{a}} {{b}}", vars)
+	assert.EqualError(t, err, "Undefined variables: a, b")
+}
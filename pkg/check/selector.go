@@ -0,0 +1,259 @@
+package check
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+)
+
+// Selector selects elements from a HTML or XML response body
+type Selector struct {
+	expr  string
+	kind  string
+	xpath *xpath.Expr
+	css   cascadia.SelectorGroup
+	isXML bool
+}
+
+// element is an element selected from a HTML or XML document
+type element interface {
+	text() string
+	attr(name string) (string, bool)
+}
+
+// NewCSSSelector creates a selector evaluating a CSS selector on a HTML body
+func NewCSSSelector(expr string) (*Selector, error) {
+	sel, err := cascadia.ParseGroup(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid CSS selector '%s'", expr)
+	}
+
+	return &Selector{
+		expr: expr,
+		kind: "CSS",
+		css:  sel,
+	}, nil
+}
+
+// NewXPathSelector creates a selector evaluating an XPath expression on a HTML or XML (xml = true) body
+func NewXPathSelector(expr string, xml bool) (*Selector, error) {
+	x, err := xpath.Compile(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid XPath expression '%s'", expr)
+	}
+
+	return &Selector{
+		expr:  expr,
+		kind:  "XPath",
+		xpath: x,
+		isXML: xml,
+	}, nil
+}
+
+func (s *Selector) String() string {
+	return fmt.Sprintf("%s %s", s.kind, s.expr)
+}
+
+func (s *Selector) selectElements(r *Response) ([]element, error) {
+	if s.isXML {
+		doc, err := r.XML()
+		if err != nil {
+			return nil, err
+		}
+
+		nodes := xmlquery.QuerySelectorAll(doc, s.xpath)
+		res := make([]element, len(nodes))
+		for i, n := range nodes {
+			res[i] = xmlElement{n}
+		}
+
+		return res, nil
+	}
+
+	doc, err := r.HTML()
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*html.Node
+	if s.xpath != nil {
+		nodes = htmlquery.QuerySelectorAll(doc, s.xpath)
+	} else {
+		nodes = cascadia.QueryAll(doc, s.css)
+	}
+
+	res := make([]element, len(nodes))
+	for i, n := range nodes {
+		res[i] = htmlElement{n}
+	}
+
+	return res, nil
+}
+
+func (s *Selector) first(r *Response) (element, error) {
+	elements, err := s.selectElements(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("%s: no element found", s)
+	}
+
+	return elements[0], nil
+}
+
+// AssertSelectorExists tests if the selector matches at least one element
+func (c *Check) AssertSelectorExists(s *Selector) {
+	c.addAssertion(fmt.Sprintf("%s exists", s), func(r *Response) error {
+		_, err := s.first(r)
+		return err
+	})
+}
+
+// AssertSelectorText tests if the text of the first element matched by the selector equals the expected text
+// (leading and trailing white space is ignored)
+func (c *Check) AssertSelectorText(s *Selector, expected string) {
+	c.addAssertion(fmt.Sprintf("%s text == '%s'", s, expected), func(r *Response) error {
+		e, err := s.first(r)
+		if err != nil {
+			return err
+		}
+
+		if actual := e.text(); actual != expected {
+			return fmt.Errorf("%s: text is '%s' (expected: '%s')", s, actual, expected)
+		}
+
+		return nil
+	})
+}
+
+// AssertSelectorTextMatches tests if the text of the first element matched by the selector matches the regex
+func (c *Check) AssertSelectorTextMatches(s *Selector, regex string) {
+	c.addAssertion(fmt.Sprintf("%s text matches '%s'", s, regex), func(r *Response) error {
+		re, err := regexp.Compile(regex)
+		if err != nil {
+			return errors.Wrap(err, "Invalid regex")
+		}
+
+		e, err := s.first(r)
+		if err != nil {
+			return err
+		}
+
+		if actual := e.text(); !re.MatchString(actual) {
+			return fmt.Errorf("%s: text '%s' does not match '%s'", s, actual, regex)
+		}
+
+		return nil
+	})
+}
+
+// AssertSelectorAttribute tests if the attribute of the first element matched by the selector has the expected value
+func (c *Check) AssertSelectorAttribute(s *Selector, name, expected string) {
+	c.addAssertion(fmt.Sprintf("%s attribute %s == '%s'", s, name, expected), func(r *Response) error {
+		e, err := s.first(r)
+		if err != nil {
+			return err
+		}
+
+		actual, found := e.attr(name)
+		if !found {
+			return fmt.Errorf("%s: attribute %s not found", s, name)
+		}
+
+		if actual != expected {
+			return fmt.Errorf("%s: attribute %s is '%s' (expected: '%s')", s, name, actual, expected)
+		}
+
+		return nil
+	})
+}
+
+// AssertSelectorCount tests the number of elements matched by the selector
+func (c *Check) AssertSelectorCount(s *Selector, op Operator, expected int) {
+	c.addAssertion(fmt.Sprintf("count(%s) %s %d", s, op, expected), func(r *Response) error {
+		elements, err := s.selectElements(r)
+		if err != nil {
+			return err
+		}
+
+		if !op.Compare(float64(len(elements)), float64(expected)) {
+			return fmt.Errorf("%s: found %d elements (expected: %s %d)", s, len(elements), op, expected)
+		}
+
+		return nil
+	})
+}
+
+// HTML returns the parsed HTML body. The body is parsed only once.
+func (r *Response) HTML() (*html.Node, error) {
+	if r.html == nil {
+		doc, err := htmlquery.Parse(bytes.NewReader(r.Body))
+		if err != nil {
+			return nil, errors.Wrap(err, "Could not parse body as HTML")
+		}
+
+		r.html = doc
+	}
+
+	return r.html, nil
+}
+
+// XML returns the parsed XML body. The body is parsed only once.
+func (r *Response) XML() (*xmlquery.Node, error) {
+	if r.xml == nil {
+		doc, err := xmlquery.Parse(bytes.NewReader(r.Body))
+		if err != nil {
+			return nil, errors.Wrap(err, "Could not parse body as XML")
+		}
+
+		r.xml = doc
+	}
+
+	return r.xml, nil
+}
+
+type htmlElement struct {
+	n *html.Node
+}
+
+func (e htmlElement) text() string {
+	return strings.TrimSpace(htmlquery.InnerText(e.n))
+}
+
+func (e htmlElement) attr(name string) (string, bool) {
+	for _, a := range e.n.Attr {
+		if a.Key == name {
+			return a.Val, true
+		}
+	}
+
+	return "", false
+}
+
+type xmlElement struct {
+	n *xmlquery.Node
+}
+
+func (e xmlElement) text() string {
+	return strings.TrimSpace(e.n.InnerText())
+}
+
+func (e xmlElement) attr(name string) (string, bool) {
+	for _, a := range e.n.Attr {
+		if a.Name.Local == name || (len(a.Name.Space) > 0 && a.Name.Space+":"+a.Name.Local == name) {
+			return a.Value, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,154 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const htmlBody = `<html>
+<head><title>Mauve Shop</title></head>
+<body>
+  <a class="logo" href="/">Mauve</a>
+  <ul id="products">
+    <li class="product">Guitar</li>
+    <li class="product">Bass</li>
+    <li class="product">Drums</li>
+  </ul>
+</body>
+</html>`
+
+const xmlBody = `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <status code="200">UP</status>
+  </soap:Body>
+</soap:Envelope>`
+
+func TestSelectorAssertions(t *testing.T) {
+	css := func(expr string) *Selector {
+		s, err := NewCSSSelector(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return s
+	}
+
+	xp := func(expr string, xml bool) *Selector {
+		s, err := NewXPathSelector(expr, xml)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		assert   func(c *Check)
+		expected Status
+		message  string
+	}{
+		{
+			name:     "css exists",
+			body:     htmlBody,
+			assert:   func(c *Check) { c.AssertSelectorExists(css("ul#products > li.product")) },
+			expected: OK,
+		},
+		{
+			name:     "css does not exist",
+			body:     htmlBody,
+			assert:   func(c *Check) { c.AssertSelectorExists(css("div.cart")) },
+			expected: Critical,
+			message:  "CSS div.cart: no element found",
+		},
+		{
+			name:     "css text",
+			body:     htmlBody,
+			assert:   func(c *Check) { c.AssertSelectorText(css("title"), "Mauve Shop") },
+			expected: OK,
+		},
+		{
+			name:     "css text differs",
+			body:     htmlBody,
+			assert:   func(c *Check) { c.AssertSelectorText(css("li.product"), "Bass") },
+			expected: Critical,
+			message:  "CSS li.product: text is 'Guitar' (expected: 'Bass')",
+		},
+		{
+			name:     "css attribute",
+			body:     htmlBody,
+			assert:   func(c *Check) { c.AssertSelectorAttribute(css("a.logo"), "href", "/") },
+			expected: OK,
+		},
+		{
+			name:     "css missing attribute",
+			body:     htmlBody,
+			assert:   func(c *Check) { c.AssertSelectorAttribute(css("a.logo"), "title", "") },
+			expected: Critical,
+			message:  "CSS a.logo: attribute title not found",
+		},
+		{
+			name:     "css count",
+			body:     htmlBody,
+			assert:   func(c *Check) { c.AssertSelectorCount(css("li.product"), GreaterOrEqual, 3) },
+			expected: OK,
+		},
+		{
+			name:     "css count fails",
+			body:     htmlBody,
+			assert:   func(c *Check) { c.AssertSelectorCount(css("li.product"), Equal, 2) },
+			expected: Critical,
+			message:  "CSS li.product: found 3 elements (expected: == 2)",
+		},
+		{
+			name:     "html xpath text matches",
+			body:     htmlBody,
+			assert:   func(c *Check) { c.AssertSelectorTextMatches(xp("//ul[@id='products']/li[last()]", false), "^Dr") },
+			expected: OK,
+		},
+		{
+			name:     "xml xpath text",
+			body:     xmlBody,
+			assert:   func(c *Check) { c.AssertSelectorText(xp("//soap:Body/status", true), "UP") },
+			expected: OK,
+		},
+		{
+			name:     "xml xpath attribute",
+			body:     xmlBody,
+			assert:   func(c *Check) { c.AssertSelectorAttribute(xp("//status", true), "code", "500") },
+			expected: Critical,
+			message:  "XPath //status: attribute code is '200' (expected: '500')",
+		},
+		{
+			name:     "invalid xml",
+			body:     "<a><b></a>",
+			assert:   func(c *Check) { c.AssertSelectorExists(xp("//b", true)) },
+			expected: Critical,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCheck(nil, "")
+			test.assert(c)
+
+			res := newResult(c.validate(&Response{Body: []byte(test.body)}))
+			assert.Equal(t, test.expected, res.Status, res.Message)
+
+			if len(test.message) > 0 {
+				assert.Equal(t, test.message, res.Message)
+			}
+		})
+	}
+}
+
+func TestInvalidSelectors(t *testing.T) {
+	_, err := NewCSSSelector("a[")
+	assert.NotNil(t, err)
+
+	_, err = NewXPathSelector("//a[", false)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,174 @@
+package check
+
+import (
+	"fmt"
+	"net"
+	"net/textproto"
+	"os"
+	"strings"
+)
+
+// StartTLS is a plaintext protocol which is upgraded to TLS by a STARTTLS command
+type StartTLS string
+
+const (
+	// StartTLSSMTP upgrades SMTP connections (EHLO, STARTTLS)
+	StartTLSSMTP StartTLS = "smtp"
+
+	// StartTLSIMAP upgrades IMAP connections (STARTTLS)
+	StartTLSIMAP StartTLS = "imap"
+
+	// StartTLSPOP3 upgrades POP3 connections (STLS)
+	StartTLSPOP3 StartTLS = "pop3"
+
+	// StartTLSFTP upgrades FTP connections (AUTH TLS)
+	StartTLSFTP StartTLS = "ftp"
+)
+
+// ParseStartTLS parses the name of a STARTTLS protocol
+func ParseStartTLS(s string) (StartTLS, error) {
+	switch p := StartTLS(strings.ToLower(s)); p {
+	case StartTLSSMTP, StartTLSIMAP, StartTLSPOP3, StartTLSFTP:
+		return p, nil
+	default:
+		return "", fmt.Errorf("Unsupported STARTTLS protocol '%s' (expected smtp, imap, pop3 or ftp)", s)
+	}
+}
+
+// WithStartTLS negotiates TLS using the plaintext protocol before the TLS handshake (TLS checks only)
+func WithStartTLS(p StartTLS) Option {
+	return func(c *Check) {
+		c.startTLS = p
+	}
+}
+
+// negotiate runs the plaintext protocol on conn until the server is ready for the TLS handshake
+func (p StartTLS) negotiate(conn net.Conn) error {
+	tc := textproto.NewConn(conn)
+
+	switch p {
+	case StartTLSSMTP:
+		return negotiateSMTP(tc)
+	case StartTLSIMAP:
+		return negotiateIMAP(tc)
+	case StartTLSPOP3:
+		return negotiatePOP3(tc)
+	case StartTLSFTP:
+		return negotiateFTP(tc)
+	default:
+		return fmt.Errorf("Unsupported STARTTLS protocol '%s'", p)
+	}
+}
+
+func negotiateSMTP(tc *textproto.Conn) error {
+	_, _, err := tc.ReadResponse(220)
+	if err != nil {
+		return err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+
+	_, msg, err := cmd(tc, 250, "EHLO %s", hostname)
+	if err != nil {
+		return err
+	}
+
+	if !hasExtension(msg, "STARTTLS") {
+		return fmt.Errorf("Server does not support STARTTLS")
+	}
+
+	_, _, err = cmd(tc, 220, "STARTTLS")
+	return err
+}
+
+func hasExtension(ehlo, ext string) bool {
+	for _, line := range strings.Split(ehlo, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strings.EqualFold(fields[0], ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func negotiateIMAP(tc *textproto.Conn) error {
+	greeting, err := tc.ReadLine()
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(greeting, "* OK") {
+		return fmt.Errorf("Unexpected greeting: %s", greeting)
+	}
+
+	err = tc.PrintfLine("a1 STARTTLS")
+	if err != nil {
+		return err
+	}
+
+	for {
+		line, err := tc.ReadLine()
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(line, "a1 ") {
+			if !strings.HasPrefix(line, "a1 OK") {
+				return fmt.Errorf("STARTTLS rejected: %s", line)
+			}
+
+			return nil
+		}
+	}
+}
+
+func negotiatePOP3(tc *textproto.Conn) error {
+	err := expectPOP3OK(tc)
+	if err != nil {
+		return err
+	}
+
+	err = tc.PrintfLine("STLS")
+	if err != nil {
+		return err
+	}
+
+	return expectPOP3OK(tc)
+}
+
+func expectPOP3OK(tc *textproto.Conn) error {
+	line, err := tc.ReadLine()
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(line, "+OK") {
+		return fmt.Errorf("Unexpected response: %s", line)
+	}
+
+	return nil
+}
+
+func negotiateFTP(tc *textproto.Conn) error {
+	_, _, err := tc.ReadResponse(220)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = cmd(tc, 234, "AUTH TLS")
+	return err
+}
+
+// cmd sends a command and reads the (multi line) response of SMTP and FTP servers
+func cmd(tc *textproto.Conn, expectCode int, format string, args ...interface{}) (int, string, error) {
+	err := tc.PrintfLine(format, args...)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return tc.ReadResponse(expectCode)
+}
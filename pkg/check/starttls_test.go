@@ -0,0 +1,196 @@
+package check
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// exchange is a command expected by a fake server and its reply
+type exchange struct {
+	command string
+	reply   string
+}
+
+func selfSignedCertificate(t *testing.T, notAfter time.Time) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mail.example.com"},
+		DNSNames:     []string{"mail.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// fakeServer accepts a single connection, sends the greeting, answers the expected commands and starts TLS
+func fakeServer(t *testing.T, cert tls.Certificate, greeting string, script []exchange) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		fmt.Fprint(conn, greeting)
+		r := bufio.NewReader(conn)
+		for _, e := range script {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			if !strings.HasPrefix(line, e.command) {
+				fmt.Fprintf(conn, "500 unexpected command\r\n")
+				return
+			}
+
+			fmt.Fprint(conn, e.reply)
+		}
+
+		tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}}).Handshake()
+	}()
+
+	return l.Addr().String()
+}
+
+func TestStartTLS(t *testing.T) {
+	tests := []struct {
+		protocol StartTLS
+		greeting string
+		script   []exchange
+		expected Status
+	}{
+		{
+			protocol: StartTLSSMTP,
+			greeting: "220-mail.example.com ESMTP\r\n220 ready\r\n",
+			script: []exchange{
+				{command: "EHLO ", reply: "250-mail.example.com\r\n250-PIPELINING\r\n250-STARTTLS\r\n250 8BITMIME\r\n"},
+				{command: "STARTTLS", reply: "220 2.0.0 Ready to start TLS\r\n"},
+			},
+			expected: Warning,
+		},
+		{
+			protocol: StartTLSIMAP,
+			greeting: "* OK IMAP4rev1 ready\r\n",
+			script: []exchange{
+				{command: "a1 STARTTLS", reply: "* CAPABILITY IMAP4rev1\r\na1 OK Begin TLS negotiation now\r\n"},
+			},
+			expected: Warning,
+		},
+		{
+			protocol: StartTLSPOP3,
+			greeting: "+OK POP3 ready\r\n",
+			script: []exchange{
+				{command: "STLS", reply: "+OK Begin TLS negotiation\r\n"},
+			},
+			expected: Warning,
+		},
+		{
+			protocol: StartTLSFTP,
+			greeting: "220-Welcome\r\n220 FTP ready\r\n",
+			script: []exchange{
+				{command: "AUTH TLS", reply: "234 AUTH TLS successful\r\n"},
+			},
+			expected: Warning,
+		},
+		{
+			protocol: StartTLSSMTP,
+			greeting: "220 mail.example.com ESMTP\r\n",
+			script: []exchange{
+				{command: "EHLO ", reply: "250-mail.example.com\r\n250 8BITMIME\r\n"},
+			},
+			expected: Critical,
+		},
+		{
+			protocol: StartTLSIMAP,
+			greeting: "* OK IMAP4rev1 ready\r\n",
+			script: []exchange{
+				{command: "a1 STARTTLS", reply: "a1 BAD unknown command\r\n"},
+			},
+			expected: Critical,
+		},
+		{
+			protocol: StartTLSPOP3,
+			greeting: "-ERR go away\r\n",
+			expected: Critical,
+		},
+	}
+
+	cert := selfSignedCertificate(t, time.Now().Add(20*24*time.Hour+time.Hour))
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s %v", test.protocol, test.expected), func(t *testing.T) {
+			addr := fakeServer(t, cert, test.greeting, test.script)
+
+			c, err := NewTLSCheck(addr, "mail.example.com", &tls.Config{InsecureSkipVerify: true}, time.Second, WithStartTLS(test.protocol))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.AssertCertificateExpireThresholds(mustParseRange("30:"), mustParseRange("14:"))
+			c.AssertCertificateHostname("")
+
+			res := c.Run()
+			assert.Equal(t, test.expected, res.Status, res.Message)
+			if test.expected == Warning {
+				assert.Equal(t, "Certificate 'CN=mail.example.com' expires in 20 days (warning threshold: 30:)", res.Message)
+			} else {
+				assert.True(t, strings.HasPrefix(res.Message, "STARTTLS negotiation ("+string(test.protocol)+") failed"), res.Message)
+			}
+		})
+	}
+}
+
+func TestStartTLSTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	c, err := NewTLSCheck(l.Addr().String(), "", nil, 100*time.Millisecond, WithStartTLS(StartTLSSMTP))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Timeout exceeded (100ms)", res.Message)
+}
+
+func TestParseStartTLS(t *testing.T) {
+	p, err := ParseStartTLS("SMTP")
+	assert.NoError(t, err)
+	assert.Equal(t, StartTLSSMTP, p)
+
+	_, err = ParseStartTLS("xmpp")
+	assert.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package check
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status is the state of a check result (following the nagios plugin API)
+type Status int
+
+const (
+	// OK means all assertions were successful
+	OK Status = iota
+
+	// Warning means a warning threshold was exceeded
+	Warning
+
+	// Critical means an assertion failed or a critical threshold was exceeded
+	Critical
+
+	// Unknown means the check could not be performed
+	Unknown
+)
+
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "OK"
+	case Warning:
+		return "WARNING"
+	case Critical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Result is the outcome of a check run
+type Result struct {
+	// Status is the most severe status of all assertions
+	Status Status
+
+	// Message summarizes the result
+	Message string
+
+	// Assertions contains the result of each assertion evaluated
+	Assertions []AssertionResult
+}
+
+// AssertionResult is the outcome of a single assertion
+type AssertionResult struct {
+	Name    string
+	Status  Status
+	Message string
+}
+
+func newAssertionResult(name string, err error) AssertionResult {
+	res := AssertionResult{
+		Name:   name,
+		Status: statusOf(err),
+	}
+
+	if err != nil {
+		res.Message = err.Error()
+	}
+
+	return res
+}
+
+func newResult(assertions []AssertionResult) *Result {
+	res := &Result{
+		Status:     OK,
+		Assertions: assertions,
+	}
+
+	failed := []string{}
+	for _, a := range assertions {
+		if a.Status == OK {
+			continue
+		}
+
+		failed = append(failed, a.Message)
+		if a.Status > res.Status {
+			res.Status = a.Status
+		}
+	}
+
+	switch len(failed) {
+	case 0:
+		res.Message = fmt.Sprintf("%d assertions passed", len(assertions))
+	case 1:
+		res.Message = failed[0]
+	default:
+		res.Message = fmt.Sprintf("%d of %d assertions failed: %s", len(failed), len(assertions), strings.Join(failed, "; "))
+	}
+
+	return res
+}
+
+// failure is an assertion error with a specific severity
+type failure struct {
+	status Status
+	msg    string
+}
+
+func (f *failure) Error() string {
+	return f.msg
+}
+
+func statusOf(err error) Status {
+	if err == nil {
+		return OK
+	}
+
+	if f, ok := err.(*failure); ok {
+		return f.status
+	}
+
+	return Critical
+}
@@ -0,0 +1,216 @@
+package check
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "1.0",
+	tls.VersionTLS11: "1.1",
+	tls.VersionTLS12: "1.2",
+	tls.VersionTLS13: "1.3",
+}
+
+// ParseTLSVersion parses a TLS version (e.g. 1.2)
+func ParseTLSVersion(s string) (uint16, error) {
+	for v, name := range tlsVersions {
+		if strings.TrimPrefix(s, "TLS") == name || strings.TrimPrefix(s, "TLS ") == name {
+			return v, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Invalid TLS version '%s' (expected 1.0, 1.1, 1.2 or 1.3)", s)
+}
+
+func tlsVersionName(v uint16) string {
+	if name, found := tlsVersions[v]; found {
+		return "TLS " + name
+	}
+
+	return fmt.Sprintf("0x%04x", v)
+}
+
+// ParseCipherSuite parses the name of a cipher suite (e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+func ParseCipherSuite(name string) (uint16, error) {
+	for _, suites := range [][]*tls.CipherSuite{tls.CipherSuites(), tls.InsecureCipherSuites()} {
+		for _, s := range suites {
+			if s.Name == name {
+				return s.ID, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("Unknown cipher suite '%s'", name)
+}
+
+func connectionState(r *Response) (*tls.ConnectionState, error) {
+	if r.TLS == nil {
+		return nil, fmt.Errorf("No TLS connection")
+	}
+
+	return r.TLS, nil
+}
+
+func leafCertificate(r *Response) (*x509.Certificate, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("No certificate returned")
+	}
+
+	return r.TLS.PeerCertificates[0], nil
+}
+
+// AssertTLSMinVersion tests if at least the specified TLS version was negotiated
+func (c *Check) AssertTLSMinVersion(version uint16) {
+	c.addAssertion(fmt.Sprintf("TLS version at least %s", tlsVersionName(version)), func(r *Response) error {
+		s, err := connectionState(r)
+		if err != nil {
+			return err
+		}
+
+		if s.Version < version {
+			return fmt.Errorf("Negotiated %s", tlsVersionName(s.Version))
+		}
+
+		return nil
+	})
+}
+
+// AssertCipherSuiteIn tests if the negotiated cipher suite is one of the allowed suites
+func (c *Check) AssertCipherSuiteIn(suites []uint16) {
+	names := make([]string, len(suites))
+	for i, s := range suites {
+		names[i] = tls.CipherSuiteName(s)
+	}
+
+	c.addAssertion(fmt.Sprintf("Cipher suite in %v", names), func(r *Response) error {
+		s, err := connectionState(r)
+		if err != nil {
+			return err
+		}
+
+		for _, suite := range suites {
+			if s.CipherSuite == suite {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Negotiated cipher suite %s is not allowed", tls.CipherSuiteName(s.CipherSuite))
+	})
+}
+
+// AssertCertificateHostname tests if the certificate is valid for the hostname (SAN).
+// If hostname is empty the hostname of the requested URL (after following redirects) is used.
+func (c *Check) AssertCertificateHostname(hostname string) {
+	name := "Certificate valid for requested hostname"
+	if len(hostname) > 0 {
+		name = fmt.Sprintf("Certificate valid for '%s'", hostname)
+	}
+
+	c.addAssertion(name, func(r *Response) error {
+		cert, err := leafCertificate(r)
+		if err != nil {
+			return err
+		}
+
+		h := hostname
+		if len(h) == 0 {
+			h, err = r.hostname()
+			if err != nil {
+				return err
+			}
+		}
+
+		err = cert.VerifyHostname(h)
+		if err != nil {
+			return fmt.Errorf("Certificate is not valid for '%s' (SANs: %s)", h, strings.Join(subjectAltNames(cert), ", "))
+		}
+
+		return nil
+	})
+}
+
+// AssertIssuerCommonName tests the common name of the issuer of the certificate
+func (c *Check) AssertIssuerCommonName(expected string) {
+	c.addAssertion(fmt.Sprintf("Issuer CN is '%s'", expected), func(r *Response) error {
+		cert, err := leafCertificate(r)
+		if err != nil {
+			return err
+		}
+
+		if cert.Issuer.CommonName != expected {
+			return fmt.Errorf("Certificate issued by '%s'", cert.Issuer.CommonName)
+		}
+
+		return nil
+	})
+}
+
+// AssertIssuerOrganization tests if the organization of the issuer of the certificate matches
+func (c *Check) AssertIssuerOrganization(expected string) {
+	c.addAssertion(fmt.Sprintf("Issuer organization is '%s'", expected), func(r *Response) error {
+		cert, err := leafCertificate(r)
+		if err != nil {
+			return err
+		}
+
+		for _, o := range cert.Issuer.Organization {
+			if o == expected {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Certificate issued by organization %v", cert.Issuer.Organization)
+	})
+}
+
+// AssertOCSPStapled tests if the server staples an OCSP response
+func (c *Check) AssertOCSPStapled() {
+	c.addAssertion("OCSP response stapled", func(r *Response) error {
+		s, err := connectionState(r)
+		if err != nil {
+			return err
+		}
+
+		if len(s.OCSPResponse) == 0 {
+			return fmt.Errorf("No OCSP response stapled")
+		}
+
+		return nil
+	})
+}
+
+func subjectAltNames(cert *x509.Certificate) []string {
+	names := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+
+	return names
+}
+
+// writeTLSDetails writes the negotiated connection parameters and the presented certificates
+func writeTLSDetails(w io.Writer, s *tls.ConnectionState) {
+	fmt.Fprintf(w, "TLS: %s, %s\n", tlsVersionName(s.Version), tls.CipherSuiteName(s.CipherSuite))
+
+	if len(s.NegotiatedProtocol) > 0 {
+		fmt.Fprintf(w, "ALPN: %s\n", s.NegotiatedProtocol)
+	}
+
+	fmt.Fprintf(w, "OCSP stapled: %v\n", len(s.OCSPResponse) > 0)
+
+	for i, cert := range s.PeerCertificates {
+		fmt.Fprintf(w, "Certificate #%d: %s\n", i, cert.Subject)
+		fmt.Fprintf(w, "  Issuer: %s\n", cert.Issuer)
+		fmt.Fprintf(w, "  Valid: %v - %v\n", cert.NotBefore, cert.NotAfter)
+
+		if names := subjectAltNames(cert); len(names) > 0 {
+			fmt.Fprintf(w, "  SANs: %s\n", strings.Join(names, ", "))
+		}
+	}
+
+	fmt.Fprintln(w, "")
+}
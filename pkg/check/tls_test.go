@@ -0,0 +1,177 @@
+package check
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTLSServer(cfg *tls.Config) *httptest.Server {
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	s.TLS = cfg
+	s.StartTLS()
+
+	return s
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	v, err := ParseTLSVersion("1.2")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), v)
+
+	v, err = ParseTLSVersion("TLS1.3")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), v)
+
+	_, err = ParseTLSVersion("2.0")
+	assert.Error(t, err)
+}
+
+func TestParseCipherSuite(t *testing.T) {
+	id, err := ParseCipherSuite("TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+	assert.NoError(t, err)
+	assert.Equal(t, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, id)
+
+	_, err = ParseCipherSuite("TLS_UNKNOWN")
+	assert.Error(t, err)
+}
+
+func TestAssertTLSMinVersion(t *testing.T) {
+	s := newTLSServer(&tls.Config{MaxVersion: tls.VersionTLS12})
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL)
+	c.AssertTLSMinVersion(tls.VersionTLS12)
+	assert.Equal(t, OK, c.Run().Status)
+
+	c = NewCheck(s.Client(), s.URL)
+	c.AssertTLSMinVersion(tls.VersionTLS13)
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Negotiated TLS 1.2", res.Message)
+}
+
+func TestAssertCipherSuiteIn(t *testing.T) {
+	s := newTLSServer(&tls.Config{
+		MaxVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+	})
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL)
+	c.AssertCipherSuiteIn([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256})
+	assert.Equal(t, OK, c.Run().Status)
+
+	c = NewCheck(s.Client(), s.URL)
+	c.AssertCipherSuiteIn([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384})
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Negotiated cipher suite TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 is not allowed", res.Message)
+}
+
+func TestAssertCertificateHostname(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer s.Close()
+
+	tests := []struct {
+		hostname string
+		expected Status
+	}{
+		{hostname: "", expected: OK},
+		{hostname: "example.com", expected: OK},
+		{hostname: "www.mauve.de", expected: Critical},
+	}
+
+	for _, test := range tests {
+		t.Run(test.hostname, func(t *testing.T) {
+			c := NewCheck(s.Client(), s.URL)
+			c.AssertCertificateHostname(test.hostname)
+
+			res := c.Run()
+			assert.Equal(t, test.expected, res.Status, res.Message)
+		})
+	}
+}
+
+func TestAssertIssuer(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL)
+	c.AssertIssuerOrganization("Acme Co")
+	assert.Equal(t, OK, c.Run().Status)
+
+	c = NewCheck(s.Client(), s.URL)
+	c.AssertIssuerOrganization("Mauve")
+	c.AssertIssuerCommonName("Mauve CA")
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, Critical, res.Assertions[0].Status)
+	assert.Equal(t, Critical, res.Assertions[1].Status)
+}
+
+func TestAssertOCSPStapled(t *testing.T) {
+	s := newTLSServer(&tls.Config{})
+	defer s.Close()
+
+	c := NewCheck(s.Client(), s.URL)
+	c.AssertOCSPStapled()
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "No OCSP response stapled", res.Message)
+
+	s.TLS.Certificates[0].OCSPStaple = []byte("staple")
+	s.Client().CloseIdleConnections()
+	c = NewCheck(s.Client(), s.URL)
+	c.AssertOCSPStapled()
+	assert.Equal(t, OK, c.Run().Status)
+}
+
+func TestAssertTLSWithoutTLS(t *testing.T) {
+	c := NewCheck(nil, "")
+	c.AssertTLSMinVersion(tls.VersionTLS12)
+	c.AssertOCSPStapled()
+
+	res := newResult(c.validate(&Response{}))
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "No TLS connection", res.Assertions[0].Message)
+}
+
+func TestAssertIntermediateExpireThresholds(t *testing.T) {
+	resp := &Response{
+		TLS: &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{
+				{NotAfter: time.Now().Add(5 * 24 * time.Hour)},
+				{NotAfter: time.Now().Add(365 * 24 * time.Hour), Subject: pkix.Name{CommonName: "Root"}},
+				{NotAfter: time.Now().Add(20*24*time.Hour + time.Hour), Subject: pkix.Name{CommonName: "Intermediate"}},
+			},
+		},
+	}
+
+	c := NewCheck(nil, "")
+	c.AssertIntermediateExpireThresholds(mustParseRange("30:"), mustParseRange("14:"))
+
+	res := newResult(c.validate(resp))
+	assert.Equal(t, Warning, res.Status)
+	assert.Equal(t, "Certificate 'CN=Intermediate' expires in 20 days (warning threshold: 30:)", res.Message)
+}
+
+func TestTLSDetailsInDebugOutput(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer s.Close()
+
+	out := &bytes.Buffer{}
+	c := NewCheck(s.Client(), s.URL, WithDebug(out))
+	c.Run()
+
+	assert.Contains(t, out.String(), "TLS: TLS 1.3")
+	assert.Contains(t, out.String(), "Certificate #0: O=Acme Co")
+	assert.Contains(t, out.String(), "SANs: example.com")
+}
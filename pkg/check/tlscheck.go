@@ -0,0 +1,135 @@
+package check
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// tlsTarget defines the endpoint of a TLS check
+type tlsTarget struct {
+	address    string
+	serverName string
+	config     *tls.Config
+	timeout    time.Duration
+}
+
+// NewTLSCheck creates a check which only performs a TLS handshake with address (host:port) without sending a request.
+// serverName overrides the name sent via SNI and used to verify the certificate (default: host of address).
+// Only certificate and TLS assertions can be used with this kind of check.
+func NewTLSCheck(address, serverName string, cfg *tls.Config, timeout time.Duration, opts ...Option) (*Check, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid address '%s'", address)
+	}
+
+	if len(serverName) == 0 {
+		serverName = host
+	}
+
+	if cfg == nil {
+		cfg = &tls.Config{}
+	}
+
+	c := NewCheck(nil, "tls://"+address, opts...)
+	c.tlsTarget = &tlsTarget{
+		address:    address,
+		serverName: serverName,
+		config:     cfg,
+		timeout:    timeout,
+	}
+
+	return c, nil
+}
+
+func (c *Check) runTLS(ctx context.Context) *Result {
+	c.metrics = Metrics{}
+	c.response = nil
+	t := c.tlsTarget
+
+	if c.debug {
+		fmt.Fprintf(c.debugWriter, "Connect: %s (SNI: %s)\n", t.address, t.serverName)
+		if len(c.startTLS) > 0 {
+			fmt.Fprintf(c.debugWriter, "STARTTLS: %s\n", c.startTLS)
+		}
+		fmt.Fprintln(c.debugWriter, "")
+	}
+
+	start := time.Now()
+	defer func() {
+		c.metrics.Total = time.Since(start)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(httptrace.WithClientTrace(ctx, c.metrics.trace(start)), "tcp", t.address)
+	if err != nil {
+		return c.tlsError(ctx, err)
+	}
+	defer conn.Close()
+
+	if len(c.startTLS) > 0 {
+		err = c.negotiateStartTLS(ctx, conn)
+		if err != nil {
+			return c.tlsError(ctx, errors.Wrapf(err, "STARTTLS negotiation (%s) failed", c.startTLS))
+		}
+	}
+
+	cfg := t.config.Clone()
+	cfg.ServerName = t.serverName
+
+	tlsConn := tls.Client(conn, cfg)
+	handshakeStart := time.Now()
+	err = tlsConn.HandshakeContext(ctx)
+	c.metrics.TLSHandshake = time.Since(handshakeStart)
+	if err != nil {
+		return c.tlsError(ctx, errors.Wrap(err, "TLS handshake failed"))
+	}
+
+	state := tlsConn.ConnectionState()
+	c.metrics.recordCertificate(&state, c.verifiedChains)
+	c.metrics.Total = time.Since(start)
+
+	if c.debug {
+		writeTLSDetails(c.debugWriter, &state)
+	}
+
+	r := &Response{
+		URL:        c.url,
+		ServerName: t.serverName,
+		TLS:        &state,
+		Metrics:    c.metrics,
+	}
+	c.response = r
+
+	res := newResult(c.validate(r))
+	if res.Status == OK {
+		res.Message = fmt.Sprintf("TLS handshake took %v", c.metrics.Total)
+	}
+
+	return res
+}
+
+func (c *Check) negotiateStartTLS(ctx context.Context, conn net.Conn) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	return c.startTLS.negotiate(conn)
+}
+
+func (c *Check) tlsError(ctx context.Context, err error) *Result {
+	var netErr net.Error
+	if ctx.Err() == context.DeadlineExceeded || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return critical(fmt.Errorf("Timeout exceeded (%v)", c.tlsTarget.timeout))
+	}
+
+	return critical(err)
+}
@@ -0,0 +1,96 @@
+package check
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSCheck(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer s.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(s.Certificate())
+
+	out := &bytes.Buffer{}
+	c, err := NewTLSCheck(s.Listener.Addr().String(), "example.com", &tls.Config{RootCAs: roots}, time.Second, WithDebug(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.AssertCertificateHostname("")
+	c.AssertTLSMinVersion(tls.VersionTLS12)
+	c.AssertCertificateExpireThresholds(nil, mustParseRange("1:"))
+
+	res := c.Run()
+	assert.Equal(t, OK, res.Status, res.Message)
+	assert.True(t, strings.HasPrefix(res.Message, "TLS handshake took"), res.Message)
+	assert.Len(t, res.Assertions, 3)
+
+	m := c.Metrics()
+	assert.True(t, m.HasCertificate(), "certificate")
+	assert.True(t, m.TLSHandshake > 0, "tls handshake")
+	assert.Contains(t, out.String(), "SNI: example.com")
+	assert.Contains(t, out.String(), "TLS: TLS 1.3")
+}
+
+func TestTLSCheckVerificationFailed(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	defer s.Close()
+
+	c, err := NewTLSCheck(s.Listener.Addr().String(), "", nil, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.True(t, strings.HasPrefix(res.Message, "TLS handshake failed"), res.Message)
+}
+
+func TestTLSCheckConnectionRefused(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	c, err := NewTLSCheck(addr, "", nil, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, Critical, c.Run().Status)
+}
+
+func TestTLSCheckTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	c, err := NewTLSCheck(l.Addr().String(), "", nil, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := c.Run()
+	assert.Equal(t, Critical, res.Status)
+	assert.Equal(t, "Timeout exceeded (100ms)", res.Message)
+}
+
+func TestNewTLSCheckInvalidAddress(t *testing.T) {
+	_, err := NewTLSCheck("www.mauve.de", "", nil, time.Second)
+	assert.Error(t, err)
+}